@@ -1,9 +1,56 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
 )
 
+// Apache Ignite status codes
+const (
+	// StatusFailed means command failed
+	StatusFailed = 1
+	// StatusInvalidOpCode means invalid op code
+	StatusInvalidOpCode = 2
+	// StatusCacheDoesNotExist means specified cache does not exist
+	StatusCacheDoesNotExist = 1000
+	// StatusCacheExists means cache already exists
+	StatusCacheExists = 1001
+	// StatusTooManyCursors means too many cursors
+	StatusTooManyCursors = 1010
+	// StatusResourceDoesNotExist means resource does not exist
+	StatusResourceDoesNotExist = 1011
+	// StatusSecurityViolation means authorization failure
+	StatusSecurityViolation = 1012
+	// StatusAuthFailed means authentication failed
+	StatusAuthFailed = 2000
+)
+
+// Sentinel errors, use errors.Is to check error kind.
+// IgniteError matches sentinel IgniteError with the same status.
+var (
+	// ErrCacheNotFound is returned if cache does not exist
+	ErrCacheNotFound = NewError(StatusCacheDoesNotExist, "cache does not exist")
+	// ErrCacheExists is returned if cache already exists
+	ErrCacheExists = NewError(StatusCacheExists, "cache already exists")
+	// ErrTooManyCursors is returned if server limit of the open cursors is reached
+	ErrTooManyCursors = NewError(StatusTooManyCursors, "too many cursors")
+	// ErrResourceNotFound is returned if resource (e.g. cursor) does not exist
+	ErrResourceNotFound = NewError(StatusResourceDoesNotExist, "resource does not exist")
+	// ErrSecurityViolation is returned if operation is not authorized
+	ErrSecurityViolation = NewError(StatusSecurityViolation, "security violation")
+	// ErrAuthFailed is returned if server rejected handshake because of invalid credentials
+	ErrAuthFailed = NewError(StatusAuthFailed, "authentication failed")
+
+	// ErrConnectionLost is matched by ConnectionError
+	ErrConnectionLost = errors.New("connection lost")
+	// ErrHandshakeFailed is matched by HandshakeError
+	ErrHandshakeFailed = errors.New("handshake failed")
+	// ErrDecode is matched by DecodeError
+	ErrDecode = errors.New("failed to decode server response")
+	// ErrCircuitOpen is returned if request is rejected by open circuit breaker
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+)
+
 // IgniteError is Apache Ignite error
 type IgniteError struct {
 	// Apache Ignite specific status and message
@@ -12,6 +59,8 @@ type IgniteError struct {
 
 	// error
 	message string
+	// cause is wrapped error
+	cause error
 
 	error
 	fmt.Stringer
@@ -30,24 +79,136 @@ func (e *IgniteError) String() string {
 	return e.Error()
 }
 
+// Unwrap returns wrapped IgniteError or nil
+func (e *IgniteError) Unwrap() error {
+	return e.cause
+}
+
+// Is returns true if target is IgniteError with the same status
+func (e *IgniteError) Is(target error) bool {
+	t, ok := target.(*IgniteError)
+	return ok && t.IgniteStatus == e.IgniteStatus
+}
+
+// ConnectionError is returned if connection to server is lost or can't be opened
+type ConnectionError struct {
+	Err error
+}
+
+func (e *ConnectionError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns underlying network error
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true for ErrConnectionLost
+func (e *ConnectionError) Is(target error) bool {
+	return target == ErrConnectionLost
+}
+
+// HandshakeError is returned if server rejected handshake
+type HandshakeError struct {
+	// Status is error code returned by server, zero if server did not send it
+	Status int32
+	// Message is error message returned by server
+	Message string
+	// Major, Minor, Patch is protocol version supported by server
+	Major, Minor, Patch int
+}
+
+func (e *HandshakeError) Error() string {
+	return fmt.Sprintf("handshake failed: %s, server supported protocol version is v%d.%d.%d",
+		e.Message, e.Major, e.Minor, e.Patch)
+}
+
+// Is returns true for ErrHandshakeFailed and for IgniteError sentinel with the same status (e.g. ErrAuthFailed)
+func (e *HandshakeError) Is(target error) bool {
+	if target == ErrHandshakeFailed {
+		return true
+	}
+	t, ok := target.(*IgniteError)
+	return ok && e.Status != 0 && t.IgniteStatus == e.Status
+}
+
+// DecodeError is returned if server response is malformed or contains unsupported data
+type DecodeError struct {
+	message string
+	err     error
+}
+
+func (e *DecodeError) Error() string {
+	if e.err != nil {
+		return e.message + ": " + e.err.Error()
+	}
+	return e.message
+}
+
+// Unwrap returns wrapped error or nil
+func (e *DecodeError) Unwrap() error {
+	return e.err
+}
+
+// Is returns true for ErrDecode
+func (e *DecodeError) Is(target error) bool {
+	return target == ErrDecode
+}
+
+// wrapError is error with message and wrapped error
+type wrapError struct {
+	message string
+	err     error
+}
+
+func (e *wrapError) Error() string {
+	return e.message
+}
+
+func (e *wrapError) Unwrap() error {
+	return e.err
+}
+
 // Errorf formats error
 func Errorf(format string, a ...interface{}) error {
 	return fmt.Errorf(format, a...)
 }
 
+// Decodef formats DecodeError
+func Decodef(format string, a ...interface{}) error {
+	return &DecodeError{message: fmt.Sprintf(format, a...)}
+}
+
+// WrapDecodef wraps error of reading server response to DecodeError
+func WrapDecodef(err error, format string, a ...interface{}) error {
+	return &DecodeError{message: fmt.Sprintf(format, a...), err: err}
+}
+
 // NewError return error from Apache Ignite status and message
 func NewError(status int32, message string) error {
 	return &IgniteError{IgniteStatus: status, IgniteMessage: message,
 		message: fmt.Sprintf("[%d] %s", status, message)}
 }
 
-// Wrapf formats error
+// Wrapf formats error.
+// Wrapped error is available by errors.Unwrap, error is not changed.
+// IgniteError is wrapped into the new IgniteError with the same status and message.
 func Wrapf(err error, format string, a ...interface{}) error {
 	m := fmt.Sprintf("%s: %s", fmt.Sprintf(format, a...), err.Error())
-	original, ok := err.(*IgniteError)
-	if ok {
-		original.message = m
-		return original
+	if original, ok := err.(*IgniteError); ok {
+		return &IgniteError{IgniteStatus: original.IgniteStatus, IgniteMessage: original.IgniteMessage,
+			message: m, cause: original}
 	}
-	return Errorf(m)
+	return &wrapError{message: m, err: err}
+}
+
+// Is reports whether any error in err's chain matches target (see errors.Is of the standard library)
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target (see errors.As of the standard library)
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
 }
@@ -134,3 +134,69 @@ func TestIgniteError_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestWrapf_Unwrap(t *testing.T) {
+	original := NewError(StatusCacheDoesNotExist, "cache does not exist")
+	io := Errorf("EOF")
+
+	tests := []struct {
+		name        string
+		err         error
+		target      error
+		wantMessage string
+		wantIgnite  bool
+		wantIO      bool
+	}{
+		{
+			name:        "1",
+			err:         Wrapf(Wrapf(original, "failed to get value"), "request failed"),
+			target:      ErrCacheNotFound,
+			wantMessage: "request failed: failed to get value: [1000] cache does not exist",
+			wantIgnite:  true,
+		},
+		{
+			name:        "2",
+			err:         Wrapf(&ConnectionError{Err: io}, "failed to get value"),
+			target:      ErrConnectionLost,
+			wantMessage: "failed to get value: EOF",
+			wantIO:      true,
+		},
+		{
+			name:        "3",
+			err:         Wrapf(WrapDecodef(io, "failed to read status"), "failed to get value"),
+			target:      ErrDecode,
+			wantMessage: "failed to get value: failed to read status: EOF",
+			wantIO:      true,
+		},
+		{
+			name:        "4",
+			err:         Wrapf(&HandshakeError{Status: StatusAuthFailed, Message: "bad password", Major: 1, Minor: 1}, "failed to connect"),
+			target:      ErrAuthFailed,
+			wantMessage: "failed to connect: handshake failed: bad password, server supported protocol version is v1.1.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Error() != tt.wantMessage {
+				t.Errorf("Wrapf() = %v, want %v", tt.err.Error(), tt.wantMessage)
+			}
+			if !Is(tt.err, tt.target) {
+				t.Errorf("Is(%v, %v) = false, want true", tt.err, tt.target)
+			}
+			if Is(tt.err, ErrCacheExists) {
+				t.Errorf("Is(%v, ErrCacheExists) = true, want false", tt.err)
+			}
+			var ie *IgniteError
+			if As(tt.err, &ie) != tt.wantIgnite {
+				t.Errorf("As(%v, *IgniteError) = %v, want %v", tt.err, !tt.wantIgnite, tt.wantIgnite)
+			}
+			if Is(tt.err, io) != tt.wantIO {
+				t.Errorf("Is(%v, %v) = %v, want %v", tt.err, io, !tt.wantIO, tt.wantIO)
+			}
+		})
+	}
+
+	if original.Error() != "[1000] cache does not exist" {
+		t.Errorf("Wrapf() changed original error: %v", original)
+	}
+}
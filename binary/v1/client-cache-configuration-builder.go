@@ -0,0 +1,502 @@
+package ignite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CacheAtomicityMode is cache atomicity mode (CacheAtomicityModeTransactional or CacheAtomicityModeAtomic)
+type CacheAtomicityMode int32
+
+// CacheMode is cache mode (CacheModeLocal, CacheModeReplicated or CacheModePartitioned)
+type CacheMode int32
+
+// RebalanceMode is cache rebalance mode (RebalanceModeSync, RebalanceModeASync or RebalanceModeNone)
+type RebalanceMode int32
+
+// WriteSynchronizationMode is cache write synchronization mode
+// (WriteSynchronizationModeFullSync, WriteSynchronizationModeFullASync or WriteSynchronizationModePrimarySync)
+type WriteSynchronizationMode int32
+
+// PartitionLossPolicy is partition loss policy (PartitionLossPolicyReadOnlySafe, PartitionLossPolicyReadOnlyAll,
+// PartitionLossPolicyReadWriteSafe, PartitionLossPolicyReadWriteAll or PartitionLossPolicyIgnore)
+type PartitionLossPolicy int32
+
+// enumName returns name of the enum value or "UNKNOWN(v)"
+func enumName(names []string, v int32) string {
+	if v >= 0 && int(v) < len(names) {
+		return names[v]
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", v)
+}
+
+var (
+	cacheAtomicityModeNames       = []string{"TRANSACTIONAL", "ATOMIC"}
+	cacheModeNames                = []string{"LOCAL", "REPLICATED", "PARTITIONED"}
+	rebalanceModeNames            = []string{"SYNC", "ASYNC", "NONE"}
+	writeSynchronizationModeNames = []string{"FULL_SYNC", "FULL_ASYNC", "PRIMARY_SYNC"}
+	partitionLossPolicyNames      = []string{"READ_ONLY_SAFE", "READ_ONLY_ALL", "READ_WRITE_SAFE", "READ_WRITE_ALL", "IGNORE"}
+	queryIndexTypeNames           = []string{"SORTED", "FULLTEXT", "GEOSPATIAL"}
+)
+
+func (m CacheAtomicityMode) String() string {
+	return enumName(cacheAtomicityModeNames, int32(m))
+}
+
+// Valid returns true if mode is known
+func (m CacheAtomicityMode) Valid() bool {
+	return m >= 0 && int(m) < len(cacheAtomicityModeNames)
+}
+
+func (m CacheMode) String() string {
+	return enumName(cacheModeNames, int32(m))
+}
+
+// Valid returns true if mode is known
+func (m CacheMode) Valid() bool {
+	return m >= 0 && int(m) < len(cacheModeNames)
+}
+
+func (m RebalanceMode) String() string {
+	return enumName(rebalanceModeNames, int32(m))
+}
+
+// Valid returns true if mode is known
+func (m RebalanceMode) Valid() bool {
+	return m >= 0 && int(m) < len(rebalanceModeNames)
+}
+
+func (m WriteSynchronizationMode) String() string {
+	return enumName(writeSynchronizationModeNames, int32(m))
+}
+
+// Valid returns true if mode is known
+func (m WriteSynchronizationMode) Valid() bool {
+	return m >= 0 && int(m) < len(writeSynchronizationModeNames)
+}
+
+func (p PartitionLossPolicy) String() string {
+	return enumName(partitionLossPolicyNames, int32(p))
+}
+
+// Valid returns true if policy is known
+func (p PartitionLossPolicy) Valid() bool {
+	return p >= 0 && int(p) < len(partitionLossPolicyNames)
+}
+
+// CacheConfigurationError is returned by Validate, it contains all problems found in cache configuration
+type CacheConfigurationError struct {
+	Problems []string
+}
+
+func (e *CacheConfigurationError) Error() string {
+	return "invalid cache configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks cache configuration before it is sent to server
+// and returns CacheConfigurationError with all problems found or nil:
+// cache name is required, enum values must be known, counts and timeouts must not be negative,
+// key configurations must have type and affinity key field names,
+// index fields, aliases, key and value field names of query entities must refer to query fields.
+func (cc *CacheConfigurationRefs) Validate() error {
+	var problems []string
+	addf := func(format string, a ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+
+	if cc.Name == nil || len(*cc.Name) == 0 {
+		addf("cache name is required")
+	}
+	if cc.AtomicityMode != nil && !CacheAtomicityMode(*cc.AtomicityMode).Valid() {
+		addf("unknown atomicity mode %d", *cc.AtomicityMode)
+	}
+	if cc.CacheMode != nil && !CacheMode(*cc.CacheMode).Valid() {
+		addf("unknown cache mode %d", *cc.CacheMode)
+	}
+	if cc.RebalanceMode != nil && !RebalanceMode(*cc.RebalanceMode).Valid() {
+		addf("unknown rebalance mode %d", *cc.RebalanceMode)
+	}
+	if cc.WriteSynchronizationMode != nil && !WriteSynchronizationMode(*cc.WriteSynchronizationMode).Valid() {
+		addf("unknown write synchronization mode %d", *cc.WriteSynchronizationMode)
+	}
+	if cc.PartitionLossPolicy != nil && !PartitionLossPolicy(*cc.PartitionLossPolicy).Valid() {
+		addf("unknown partition loss policy %d", *cc.PartitionLossPolicy)
+	}
+
+	for _, v := range []struct {
+		name  string
+		value *int32
+		min   int32
+	}{
+		{"backups", cc.Backups, 0},
+		{"max concurrent async operations", cc.MaxConcurrentAsyncOperations, 0},
+		{"max query iterators", cc.MaxQueryIterators, 0},
+		{"query detail metrics size", cc.QueryDetailMetricsSize, 0},
+		{"query parallelism", cc.QueryParellelism, 1},
+		{"rebalance batch size", cc.RebalanceBatchSize, 1},
+		{"rebalance order", cc.RebalanceOrder, 0},
+		// -1 means default inline size
+		{"SQL index inline max size", cc.SQLIndexInlineMaxSize, -1},
+	} {
+		if v.value != nil && *v.value < v.min {
+			addf("%s must not be less than %d, got %d", v.name, v.min, *v.value)
+		}
+	}
+	for _, v := range []struct {
+		name  string
+		value *int64
+		min   int64
+	}{
+		{"lock timeout", cc.LockTimeout, 0},
+		{"rebalance batches prefetch count", cc.RebalanceBatchesPrefetchCount, 1},
+		// -1 means manual rebalancing
+		{"rebalance delay", cc.RebalanceDelay, -1},
+		{"rebalance throttle", cc.RebalanceThrottle, 0},
+		{"rebalance timeout", cc.RebalanceTimeout, 0},
+	} {
+		if v.value != nil && *v.value < v.min {
+			addf("%s must not be less than %d, got %d", v.name, v.min, *v.value)
+		}
+	}
+
+	for i, k := range cc.CacheKeyConfigurations {
+		if len(k.TypeName) == 0 {
+			addf("cache key configuration %d: type name is required", i)
+		}
+		if len(k.AffinityKeyFieldName) == 0 {
+			addf("cache key configuration %d: affinity key field name is required", i)
+		}
+	}
+	for i, e := range cc.QueryEntities {
+		for _, p := range validateQueryEntity(e) {
+			addf("query entity %d (%s): %s", i, e.ValueTypeName, p)
+		}
+	}
+
+	if p := cc.ExpiryPolicy; p != nil {
+		for _, d := range []struct {
+			name  string
+			value time.Duration
+		}{
+			{"create", p.Create},
+			{"update", p.Update},
+			{"access", p.Access},
+		} {
+			if !validExpiry(d.value) {
+				addf("expiry policy %s duration must not be negative, got %v", d.name, d.value)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &CacheConfigurationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateQueryEntity returns problems of query entity
+func validateQueryEntity(e QueryEntity) []string {
+	var problems []string
+	addf := func(format string, a ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+
+	if len(e.KeyTypeName) == 0 {
+		addf("key type name is required")
+	}
+	if len(e.ValueTypeName) == 0 {
+		addf("value type name is required")
+	}
+	// field names are case insensitive in SQL
+	fields := make(map[string]bool, len(e.QueryFields))
+	for i, f := range e.QueryFields {
+		switch name := strings.ToUpper(f.Name); {
+		case len(name) == 0:
+			addf("name of field %d is required", i)
+		case fields[name]:
+			addf("field \"%s\" is duplicated", f.Name)
+		default:
+			fields[name] = true
+		}
+		if len(f.TypeName) == 0 {
+			addf("type name of field \"%s\" is required", f.Name)
+		}
+	}
+	if len(e.KeyFieldName) > 0 && !fields[strings.ToUpper(e.KeyFieldName)] {
+		addf("key field \"%s\" does not exist", e.KeyFieldName)
+	}
+	if len(e.ValueFieldName) > 0 && !fields[strings.ToUpper(e.ValueFieldName)] {
+		addf("value field \"%s\" does not exist", e.ValueFieldName)
+	}
+	for _, a := range e.FieldNameAliases {
+		if !fields[strings.ToUpper(a.Name)] {
+			addf("field \"%s\" of alias \"%s\" does not exist", a.Name, a.Alias)
+		}
+	}
+	indexes := make(map[string]bool, len(e.QueryIndexes))
+	for i, idx := range e.QueryIndexes {
+		name := idx.Name
+		if len(name) == 0 {
+			// name is generated by server
+			name = fmt.Sprintf("#%d", i)
+		} else if indexes[strings.ToUpper(name)] {
+			addf("index \"%s\" is duplicated", name)
+		} else {
+			indexes[strings.ToUpper(name)] = true
+		}
+		if int(idx.Type) >= len(queryIndexTypeNames) {
+			addf("index \"%s\" has unknown type %d", name, idx.Type)
+		}
+		if idx.InlineSize < -1 {
+			addf("index \"%s\" inline size must not be less than -1, got %d", name, idx.InlineSize)
+		}
+		if len(idx.Fields) == 0 {
+			addf("index \"%s\" has no fields", name)
+		}
+		for _, f := range idx.Fields {
+			if !fields[strings.ToUpper(f.Name)] {
+				addf("field \"%s\" of index \"%s\" does not exist", f.Name, name)
+			}
+		}
+	}
+	return problems
+}
+
+// CacheConfigBuilder builds cache configuration to create new cache:
+//
+//	cc, err := ignite.NewCacheConfigBuilder("Person").
+//		CacheMode(ignite.CacheModePartitioned).
+//		AtomicityMode(ignite.CacheAtomicityModeAtomic).
+//		Backups(1).
+//		WriteSynchronizationMode(ignite.WriteSynchronizationModePrimarySync).
+//		QueryEntity(entity).
+//		Build()
+//	if err != nil {
+//		// err is CacheConfigurationError with all problems found
+//	}
+//	err = c.CacheCreateWithConfiguration(cc)
+//
+// Properties which are not set are not sent, server uses default values.
+type CacheConfigBuilder struct {
+	cc CacheConfigurationRefs
+}
+
+// NewCacheConfigBuilder creates builder of the configuration of the cache with name
+func NewCacheConfigBuilder(name string) *CacheConfigBuilder {
+	return &CacheConfigBuilder{cc: CacheConfigurationRefs{Name: &name}}
+}
+
+// millis returns duration in milliseconds
+func millis(d time.Duration) *int64 {
+	ms := int64(d / time.Millisecond)
+	return &ms
+}
+
+// AtomicityMode sets cache atomicity mode
+func (b *CacheConfigBuilder) AtomicityMode(v CacheAtomicityMode) *CacheConfigBuilder {
+	m := int32(v)
+	b.cc.AtomicityMode = &m
+	return b
+}
+
+// Backups sets number of backups
+func (b *CacheConfigBuilder) Backups(v int32) *CacheConfigBuilder {
+	b.cc.Backups = &v
+	return b
+}
+
+// CacheMode sets cache mode
+func (b *CacheConfigBuilder) CacheMode(v CacheMode) *CacheConfigBuilder {
+	m := int32(v)
+	b.cc.CacheMode = &m
+	return b
+}
+
+// CopyOnRead sets copy on read flag
+func (b *CacheConfigBuilder) CopyOnRead(v bool) *CacheConfigBuilder {
+	b.cc.CopyOnRead = &v
+	return b
+}
+
+// DataRegionName sets data region name
+func (b *CacheConfigBuilder) DataRegionName(v string) *CacheConfigBuilder {
+	b.cc.DataRegionName = &v
+	return b
+}
+
+// EagerTTL sets eager TTL flag
+func (b *CacheConfigBuilder) EagerTTL(v bool) *CacheConfigBuilder {
+	b.cc.EagerTTL = &v
+	return b
+}
+
+// EnableStatistics sets statistics flag
+func (b *CacheConfigBuilder) EnableStatistics(v bool) *CacheConfigBuilder {
+	b.cc.EnableStatistics = &v
+	return b
+}
+
+// GroupName sets cache group name
+func (b *CacheConfigBuilder) GroupName(v string) *CacheConfigBuilder {
+	b.cc.GroupName = &v
+	return b
+}
+
+// LockTimeout sets default lock timeout, precision is millisecond
+func (b *CacheConfigBuilder) LockTimeout(v time.Duration) *CacheConfigBuilder {
+	b.cc.LockTimeout = millis(v)
+	return b
+}
+
+// MaxConcurrentAsyncOperations sets max number of the concurrent asynchronous operations
+func (b *CacheConfigBuilder) MaxConcurrentAsyncOperations(v int32) *CacheConfigBuilder {
+	b.cc.MaxConcurrentAsyncOperations = &v
+	return b
+}
+
+// MaxQueryIterators sets max number of the query iterators
+func (b *CacheConfigBuilder) MaxQueryIterators(v int32) *CacheConfigBuilder {
+	b.cc.MaxQueryIterators = &v
+	return b
+}
+
+// OnheapCacheEnabled sets on-heap cache flag
+func (b *CacheConfigBuilder) OnheapCacheEnabled(v bool) *CacheConfigBuilder {
+	b.cc.OnheapCacheEnabled = &v
+	return b
+}
+
+// PartitionLossPolicy sets partition loss policy
+func (b *CacheConfigBuilder) PartitionLossPolicy(v PartitionLossPolicy) *CacheConfigBuilder {
+	p := int32(v)
+	b.cc.PartitionLossPolicy = &p
+	return b
+}
+
+// QueryDetailMetricsSize sets size of the query detail metrics
+func (b *CacheConfigBuilder) QueryDetailMetricsSize(v int32) *CacheConfigBuilder {
+	b.cc.QueryDetailMetricsSize = &v
+	return b
+}
+
+// QueryParallelism sets query parallelism
+func (b *CacheConfigBuilder) QueryParallelism(v int32) *CacheConfigBuilder {
+	b.cc.QueryParellelism = &v
+	return b
+}
+
+// ReadFromBackup sets read from backup flag
+func (b *CacheConfigBuilder) ReadFromBackup(v bool) *CacheConfigBuilder {
+	b.cc.ReadFromBackup = &v
+	return b
+}
+
+// RebalanceBatchSize sets rebalance batch size in bytes
+func (b *CacheConfigBuilder) RebalanceBatchSize(v int32) *CacheConfigBuilder {
+	b.cc.RebalanceBatchSize = &v
+	return b
+}
+
+// RebalanceBatchesPrefetchCount sets number of the batches generated by supply node at rebalancing start
+func (b *CacheConfigBuilder) RebalanceBatchesPrefetchCount(v int64) *CacheConfigBuilder {
+	b.cc.RebalanceBatchesPrefetchCount = &v
+	return b
+}
+
+// RebalanceDelay sets rebalance delay, precision is millisecond. Negative value means manual rebalancing.
+func (b *CacheConfigBuilder) RebalanceDelay(v time.Duration) *CacheConfigBuilder {
+	if v < 0 {
+		ms := int64(-1)
+		b.cc.RebalanceDelay = &ms
+		return b
+	}
+	b.cc.RebalanceDelay = millis(v)
+	return b
+}
+
+// RebalanceMode sets rebalance mode
+func (b *CacheConfigBuilder) RebalanceMode(v RebalanceMode) *CacheConfigBuilder {
+	m := int32(v)
+	b.cc.RebalanceMode = &m
+	return b
+}
+
+// RebalanceOrder sets rebalance order
+func (b *CacheConfigBuilder) RebalanceOrder(v int32) *CacheConfigBuilder {
+	b.cc.RebalanceOrder = &v
+	return b
+}
+
+// RebalanceThrottle sets time to wait between rebalance messages, precision is millisecond
+func (b *CacheConfigBuilder) RebalanceThrottle(v time.Duration) *CacheConfigBuilder {
+	b.cc.RebalanceThrottle = millis(v)
+	return b
+}
+
+// RebalanceTimeout sets rebalance timeout, precision is millisecond
+func (b *CacheConfigBuilder) RebalanceTimeout(v time.Duration) *CacheConfigBuilder {
+	b.cc.RebalanceTimeout = millis(v)
+	return b
+}
+
+// SQLEscapeAll sets flag to escape SQL identifiers
+func (b *CacheConfigBuilder) SQLEscapeAll(v bool) *CacheConfigBuilder {
+	b.cc.SQLEscapeAll = &v
+	return b
+}
+
+// SQLIndexInlineMaxSize sets max inline size of SQL indexes
+func (b *CacheConfigBuilder) SQLIndexInlineMaxSize(v int32) *CacheConfigBuilder {
+	b.cc.SQLIndexInlineMaxSize = &v
+	return b
+}
+
+// SQLSchema sets SQL schema
+func (b *CacheConfigBuilder) SQLSchema(v string) *CacheConfigBuilder {
+	b.cc.SQLSchema = &v
+	return b
+}
+
+// WriteSynchronizationMode sets write synchronization mode
+func (b *CacheConfigBuilder) WriteSynchronizationMode(v WriteSynchronizationMode) *CacheConfigBuilder {
+	m := int32(v)
+	b.cc.WriteSynchronizationMode = &m
+	return b
+}
+
+// CacheKeyConfiguration adds affinity key field of the key type
+func (b *CacheConfigBuilder) CacheKeyConfiguration(typeName, affinityKeyFieldName string) *CacheConfigBuilder {
+	b.cc.CacheKeyConfigurations = append(b.cc.CacheKeyConfigurations,
+		CacheKeyConfiguration{TypeName: typeName, AffinityKeyFieldName: affinityKeyFieldName})
+	return b
+}
+
+// QueryEntity adds query entity
+func (b *CacheConfigBuilder) QueryEntity(v QueryEntity) *CacheConfigBuilder {
+	b.cc.QueryEntities = append(b.cc.QueryEntities, v)
+	return b
+}
+
+// ExpiryPolicy sets default expiry policy of the cache entries (protocol v1.6.0+)
+func (b *CacheConfigBuilder) ExpiryPolicy(v ExpiryPolicy) *CacheConfigBuilder {
+	b.cc.ExpiryPolicy = &v
+	return b
+}
+
+// Validate returns CacheConfigurationError with all problems of the configuration or nil
+func (b *CacheConfigBuilder) Validate() error {
+	return b.cc.Validate()
+}
+
+// Build validates configuration and returns its copy to pass to CacheCreateWithConfiguration
+// or CacheGetOrCreateWithConfiguration
+func (b *CacheConfigBuilder) Build() (*CacheConfigurationRefs, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	cc := b.cc
+	cc.CacheKeyConfigurations = append([]CacheKeyConfiguration(nil), b.cc.CacheKeyConfigurations...)
+	cc.QueryEntities = append([]QueryEntity(nil), b.cc.QueryEntities...)
+	return &cc, nil
+}
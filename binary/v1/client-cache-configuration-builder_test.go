@@ -0,0 +1,154 @@
+package ignite
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCacheConfigBuilder_Build(t *testing.T) {
+	entity := QueryEntity{
+		KeyTypeName:   "java.lang.Long",
+		ValueTypeName: "Person",
+		TableName:     "Person",
+		KeyFieldName:  "id",
+		QueryFields: []QueryField{
+			{Name: "id", TypeName: "java.lang.Long", IsKeyField: true},
+			{Name: "name", TypeName: "java.lang.String"},
+		},
+		QueryIndexes: []QueryIndex{{Name: "NAME_IDX", Type: QueryIndexTypeSorted, InlineSize: -1,
+			Fields: []Field{{Name: "NAME"}}}},
+	}
+	got, err := NewCacheConfigBuilder("Person").
+		AtomicityMode(CacheAtomicityModeAtomic).
+		CacheMode(CacheModePartitioned).
+		Backups(1).
+		RebalanceMode(RebalanceModeASync).
+		RebalanceDelay(-1).
+		LockTimeout(5*time.Second).
+		WriteSynchronizationMode(WriteSynchronizationModePrimarySync).
+		PartitionLossPolicy(PartitionLossPolicyReadWriteSafe).
+		QueryParallelism(4).
+		SQLSchema("PUBLIC").
+		CacheKeyConfiguration("Person", "id").
+		QueryEntity(entity).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	name, schema := "Person", "PUBLIC"
+	atomicity, mode, backups, rebalance, sync, loss, parallelism := int32(1), int32(2), int32(1), int32(1), int32(2), int32(2), int32(4)
+	delay, lockTimeout := int64(-1), int64(5000)
+	want := &CacheConfigurationRefs{Name: &name, AtomicityMode: &atomicity, CacheMode: &mode, Backups: &backups,
+		RebalanceMode: &rebalance, RebalanceDelay: &delay, LockTimeout: &lockTimeout, WriteSynchronizationMode: &sync,
+		PartitionLossPolicy: &loss, QueryParellelism: &parallelism, SQLSchema: &schema,
+		CacheKeyConfigurations: []CacheKeyConfiguration{{TypeName: "Person", AffinityKeyFieldName: "id"}},
+		QueryEntities:          []QueryEntity{entity}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Build() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCacheConfigurationRefs_Validate(t *testing.T) {
+	empty, name := "", "Person"
+	backups, mode, parallelism := int32(-1), int32(5), int32(0)
+	rebalanceDelay := int64(-2)
+	tests := []struct {
+		name string
+		cc   *CacheConfigurationRefs
+		want []string
+	}{
+		{
+			name: "valid",
+			cc:   &CacheConfigurationRefs{Name: &name, ExpiryPolicy: &ExpiryPolicy{Create: ExpiryEternal, Access: time.Minute}},
+		},
+		{
+			name: "without name",
+			cc:   &CacheConfigurationRefs{Name: &empty},
+			want: []string{"cache name is required"},
+		},
+		{
+			name: "invalid values",
+			cc: &CacheConfigurationRefs{Name: &name, Backups: &backups, CacheMode: &mode, AtomicityMode: &mode,
+				RebalanceMode: &mode, WriteSynchronizationMode: &mode, PartitionLossPolicy: &mode,
+				QueryParellelism: &parallelism, RebalanceDelay: &rebalanceDelay,
+				CacheKeyConfigurations: []CacheKeyConfiguration{{TypeName: "Person"}},
+				ExpiryPolicy:           &ExpiryPolicy{Update: -time.Second}},
+			want: []string{
+				"unknown atomicity mode 5",
+				"unknown cache mode 5",
+				"unknown rebalance mode 5",
+				"unknown write synchronization mode 5",
+				"unknown partition loss policy 5",
+				"backups must not be less than 0, got -1",
+				"query parallelism must not be less than 1, got 0",
+				"rebalance delay must not be less than -1, got -2",
+				"cache key configuration 0: affinity key field name is required",
+				"expiry policy update duration must not be negative, got -1s",
+			},
+		},
+		{
+			name: "invalid query entity",
+			cc: &CacheConfigurationRefs{Name: &name, QueryEntities: []QueryEntity{{
+				ValueTypeName:    "Person",
+				KeyFieldName:     "ID",
+				QueryFields:      []QueryField{{Name: "id", TypeName: "java.lang.Long"}, {Name: "ID", TypeName: "java.lang.Long"}, {Name: "name"}},
+				FieldNameAliases: []FieldNameAlias{{Name: "fullName", Alias: "FULL_NAME"}},
+				QueryIndexes: []QueryIndex{
+					{Name: "IDX", Fields: []Field{{Name: "age"}}},
+					{Name: "idx", Type: 3, Fields: []Field{{Name: "name"}}},
+					{InlineSize: -2},
+				},
+			}}},
+			want: []string{
+				"query entity 0 (Person): key type name is required",
+				"query entity 0 (Person): field \"ID\" is duplicated",
+				"query entity 0 (Person): type name of field \"name\" is required",
+				"query entity 0 (Person): field \"fullName\" of alias \"FULL_NAME\" does not exist",
+				"query entity 0 (Person): field \"age\" of index \"IDX\" does not exist",
+				"query entity 0 (Person): index \"idx\" is duplicated",
+				"query entity 0 (Person): index \"idx\" has unknown type 3",
+				"query entity 0 (Person): index \"#2\" inline size must not be less than -1, got -2",
+				"query entity 0 (Person): index \"#2\" has no fields",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cc.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			e, ok := err.(*CacheConfigurationError)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want *CacheConfigurationError", err)
+			}
+			if !reflect.DeepEqual(e.Problems, tt.want) {
+				t.Errorf("Validate() problems = %q, want %q", e.Problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheMode_String(t *testing.T) {
+	tests := []struct {
+		v    interface{ String() string }
+		want string
+	}{
+		{CacheMode(CacheModePartitioned), "PARTITIONED"},
+		{CacheAtomicityMode(CacheAtomicityModeAtomic), "ATOMIC"},
+		{RebalanceMode(RebalanceModeASync), "ASYNC"},
+		{WriteSynchronizationMode(WriteSynchronizationModeFullASync), "FULL_ASYNC"},
+		{PartitionLossPolicy(PartitionLossPolicyIgnore), "IGNORE"},
+		{CacheMode(7), "UNKNOWN(7)"},
+	}
+	for _, tt := range tests {
+		if got := tt.v.String(); got != tt.want {
+			t.Errorf("String() = %v, want %v", got, tt.want)
+		}
+	}
+}
@@ -39,8 +39,11 @@ const (
 	cacheConfigurationWriteSynchronizationModeCode      = 4
 	cacheConfigurationCacheKeyConfigurationsCode        = 401
 	cacheConfigurationQueryEntitiesCode                 = 200
+	cacheConfigurationExpiryPolicyCode                  = 407
 )
 
+// Constants are untyped, so they can be assigned to int32 fields of CacheConfiguration
+// as well as passed to CacheConfigBuilder methods with typed enums.
 const (
 	// CacheAtomicityModeTransactional is TRANSACTIONAL = 0
 	CacheAtomicityModeTransactional = 0
@@ -68,7 +71,7 @@ const (
 	// RebalanceModeSync is SYNC = 0
 	RebalanceModeSync = 0
 	// RebalanceModeASync is ASYNC = 1
-	RebalanceModeASync = 0
+	RebalanceModeASync = 1
 	// RebalanceModeNone is NONE = 2
 	RebalanceModeNone = 2
 
@@ -119,6 +122,8 @@ type CacheConfiguration struct {
 	WriteSynchronizationMode      int32
 	CacheKeyConfigurations        []CacheKeyConfiguration
 	QueryEntities                 []QueryEntity
+	// ExpiryPolicy is nil if it is not set or protocol version is less than v1.6.0
+	ExpiryPolicy *ExpiryPolicy
 }
 
 // CacheKeyConfiguration is struct
@@ -199,6 +204,8 @@ type CacheConfigurationRefs struct {
 	WriteSynchronizationMode      *int32
 	CacheKeyConfigurations        []CacheKeyConfiguration
 	QueryEntities                 []QueryEntity
+	// ExpiryPolicy is default expiry policy of the cache entries (protocol v1.6.0+)
+	ExpiryPolicy *ExpiryPolicy
 }
 
 // CacheCreateWithName Creates a cache with a given name.
@@ -506,6 +513,21 @@ func (c *client) CacheGetConfiguration(cache string, flag byte) (*CacheConfigura
 		cc.QueryEntities = append(cc.QueryEntities, qe)
 	}
 
+	// get ExpiryPolicy
+	if c.state().version.AtLeast(ProtocolVersion160) {
+		var ok bool
+		if ok, err = ReadBool(res); err != nil {
+			return nil, errors.Wrapf(err, "failed to read ExpiryPolicy flag")
+		}
+		if ok {
+			var p ExpiryPolicy
+			if p, err = readExpiryPolicy(res); err != nil {
+				return nil, errors.Wrapf(err, "failed to read ExpiryPolicy")
+			}
+			cc.ExpiryPolicy = &p
+		}
+	}
+
 	return &cc, nil
 }
 
@@ -911,6 +933,21 @@ func (c *client) cacheCreateWithConfiguration(code int16, cc *CacheConfiguration
 		}
 		req.Count++
 	}
+	if cc.ExpiryPolicy != nil {
+		if !c.state().version.AtLeast(ProtocolVersion160) {
+			return errors.Errorf("ExpiryPolicy is not supported by protocol %s, v1.6.0+ is required", c.state().version)
+		}
+		if err := WriteShort(req, cacheConfigurationExpiryPolicyCode); err != nil {
+			return errors.Wrapf(err, "failed to write ExpiryPolicy property code")
+		}
+		if err := WriteBool(req, true); err != nil {
+			return errors.Wrapf(err, "failed to write ExpiryPolicy flag")
+		}
+		if err := writeExpiryPolicy(req, *cc.ExpiryPolicy); err != nil {
+			return errors.Wrapf(err, "failed to write ExpiryPolicy property value")
+		}
+		req.Count++
+	}
 
 	// execute operation
 	if err := c.Do(req, res); err != nil {
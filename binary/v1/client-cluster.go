@@ -0,0 +1,333 @@
+package ignite
+
+import (
+	"io"
+
+	"github.com/google/uuid"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+// Cluster Group
+// See for details:
+// https://ignite.apache.org/docs/latest/binary-client-protocol/binary-client-protocol#cluster-group
+
+const (
+	clusterGroupAttributeFilterCode   = 1
+	clusterGroupServerNodesFilterCode = 2
+)
+
+const (
+	// ClusterNodeRoleAny selects both server and client nodes
+	ClusterNodeRoleAny = 0
+	// ClusterNodeRoleServer selects server nodes only
+	ClusterNodeRoleServer = 1
+	// ClusterNodeRoleClient selects client nodes only
+	ClusterNodeRoleClient = 2
+)
+
+// ClusterGroupProjection describes subset of cluster nodes.
+// Zero value selects all nodes of the cluster.
+type ClusterGroupProjection struct {
+	// Attributes selects nodes which have all the attributes with given values.
+	Attributes map[string]interface{}
+
+	// Role selects nodes by role.
+	// ClusterNodeRoleAny = 0
+	// ClusterNodeRoleServer = 1
+	// ClusterNodeRoleClient = 2
+	Role byte
+}
+
+// ClusterNodeVersion is Apache Ignite product version of the node
+type ClusterNodeVersion struct {
+	Major, Minor, Maintenance byte
+	Stage                     string
+	RevisionTimestamp         int64
+	RevisionHash              []byte
+}
+
+// ClusterNode describes cluster node
+type ClusterNode struct {
+	// Node ID
+	ID uuid.UUID
+
+	// Node attributes
+	Attributes map[string]interface{}
+
+	// Addresses of the node
+	Addresses []string
+
+	// Host names of the node
+	HostNames []string
+
+	// Node order within grid topology
+	Order int64
+
+	// Whether this node is the node client is connected to
+	IsLocal bool
+
+	// Whether this node is daemon node
+	IsDaemon bool
+
+	// Whether this node is client node
+	IsClient bool
+
+	// Consistent globally unique node ID
+	ConsistentID interface{}
+
+	// Node version
+	Version ClusterNodeVersion
+}
+
+// ClusterGroupGetNodeIDs returns IDs of the cluster nodes matching the projection.
+func (c *client) ClusterGroupGetNodeIDs(prj ClusterGroupProjection) ([]uuid.UUID, error) {
+	if !c.FeatureSupported(FeatureClusterGroups) {
+		return nil, errors.Errorf("OP_CLUSTER_GROUP_GET_NODE_IDS operation is not supported by server (protocol %s)", c.state().version)
+	}
+
+	// request and response
+	req := NewRequestOperation(OpClusterGroupGetNodeIDs)
+	res := NewResponseOperation(req.UID)
+
+	// set parameters
+	// topology version is unknown so server always returns node IDs
+	if err := WriteLong(req, 0); err != nil {
+		return nil, errors.Wrapf(err, "failed to write topology version")
+	}
+	if err := writeClusterGroupProjection(req, prj); err != nil {
+		return nil, err
+	}
+
+	// execute operation
+	if err := c.Do(req, res); err != nil {
+		return nil, errors.Wrapf(err, "failed to execute OP_CLUSTER_GROUP_GET_NODE_IDS operation")
+	}
+	if err := res.CheckStatus(); err != nil {
+		return nil, err
+	}
+
+	return readClusterNodeIDs(res)
+}
+
+// ClusterGroupGetNodeInfo returns information about the cluster nodes with given IDs.
+func (c *client) ClusterGroupGetNodeInfo(ids []uuid.UUID) ([]ClusterNode, error) {
+	if !c.FeatureSupported(FeatureClusterGroups) {
+		return nil, errors.Errorf("OP_CLUSTER_GROUP_GET_NODE_INFO operation is not supported by server (protocol %s)", c.state().version)
+	}
+
+	// request and response
+	req := NewRequestOperation(OpClusterGroupGetNodeInfo)
+	res := NewResponseOperation(req.UID)
+
+	// set parameters
+	if err := WriteInt(req, int32(len(ids))); err != nil {
+		return nil, errors.Wrapf(err, "failed to write node ID count")
+	}
+	for i, id := range ids {
+		if err := WriteUUID(req, id); err != nil {
+			return nil, errors.Wrapf(err, "failed to write node ID with index %d", i)
+		}
+	}
+
+	// execute operation
+	if err := c.Do(req, res); err != nil {
+		return nil, errors.Wrapf(err, "failed to execute OP_CLUSTER_GROUP_GET_NODE_INFO operation")
+	}
+	if err := res.CheckStatus(); err != nil {
+		return nil, err
+	}
+
+	// read response data
+	count, err := readLength(res)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read node count")
+	}
+	nodes := make([]ClusterNode, 0, int(count))
+	for i := 0; i < int(count); i++ {
+		n, err := readClusterNode(res)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read node with index %d", i)
+		}
+		nodes = append(nodes, n)
+	}
+
+	return nodes, nil
+}
+
+// writeClusterGroupProjection writes cluster group projection filters
+func writeClusterGroupProjection(w io.Writer, prj ClusterGroupProjection) error {
+	count := len(prj.Attributes)
+	if prj.Role != ClusterNodeRoleAny {
+		count++
+	}
+	if count == 0 {
+		if err := WriteBool(w, false); err != nil {
+			return errors.Wrapf(err, "failed to write has filter flag")
+		}
+		return nil
+	}
+
+	if err := WriteBool(w, true); err != nil {
+		return errors.Wrapf(err, "failed to write has filter flag")
+	}
+	if err := WriteInt(w, int32(count)); err != nil {
+		return errors.Wrapf(err, "failed to write filter count")
+	}
+	for k, v := range prj.Attributes {
+		if err := WriteShort(w, clusterGroupAttributeFilterCode); err != nil {
+			return errors.Wrapf(err, "failed to write attribute filter code")
+		}
+		if err := WriteOString(w, k); err != nil {
+			return errors.Wrapf(err, "failed to write attribute name")
+		}
+		if err := WriteObject(w, v); err != nil {
+			return errors.Wrapf(err, "failed to write value of attribute \"%s\"", k)
+		}
+	}
+	switch prj.Role {
+	case ClusterNodeRoleAny:
+	case ClusterNodeRoleServer, ClusterNodeRoleClient:
+		if err := WriteShort(w, clusterGroupServerNodesFilterCode); err != nil {
+			return errors.Wrapf(err, "failed to write server nodes filter code")
+		}
+		if err := WriteBool(w, prj.Role == ClusterNodeRoleServer); err != nil {
+			return errors.Wrapf(err, "failed to write server nodes flag")
+		}
+	default:
+		return errors.Errorf("unsupported cluster node role: %d", prj.Role)
+	}
+	return nil
+}
+
+// readClusterNodeIDs reads node IDs of OP_CLUSTER_GROUP_GET_NODE_IDS response
+func readClusterNodeIDs(r io.Reader) ([]uuid.UUID, error) {
+	changed, err := ReadBool(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read topology changed flag")
+	}
+	if !changed {
+		return []uuid.UUID{}, nil
+	}
+	if _, err = ReadLong(r); err != nil {
+		return nil, errors.Wrapf(err, "failed to read topology version")
+	}
+	count, err := readLength(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read node ID count")
+	}
+	ids := make([]uuid.UUID, 0, int(count))
+	for i := 0; i < int(count); i++ {
+		id, err := ReadUUID(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read node ID with index %d", i)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// readClusterNode reads node information of OP_CLUSTER_GROUP_GET_NODE_INFO response
+func readClusterNode(r io.Reader) (ClusterNode, error) {
+	var n ClusterNode
+	var err error
+
+	if n.ID, err = ReadUUID(r); err != nil {
+		return n, errors.Wrapf(err, "failed to read node ID")
+	}
+
+	// read attributes
+	count, err := readLength(r)
+	if err != nil {
+		return n, errors.Wrapf(err, "failed to read attribute count")
+	}
+	n.Attributes = make(map[string]interface{}, int(count))
+	for i := 0; i < int(count); i++ {
+		k, err := ReadOString(r)
+		if err != nil {
+			return n, errors.Wrapf(err, "failed to read attribute name with index %d", i)
+		}
+		if n.Attributes[k], err = ReadObject(r); err != nil {
+			return n, errors.Wrapf(err, "failed to read value of attribute \"%s\"", k)
+		}
+	}
+
+	if n.Addresses, err = readStringCollection(r); err != nil {
+		return n, errors.Wrapf(err, "failed to read addresses")
+	}
+	if n.HostNames, err = readStringCollection(r); err != nil {
+		return n, errors.Wrapf(err, "failed to read host names")
+	}
+	if n.Order, err = ReadLong(r); err != nil {
+		return n, errors.Wrapf(err, "failed to read order")
+	}
+	if n.IsLocal, err = ReadBool(r); err != nil {
+		return n, errors.Wrapf(err, "failed to read is local flag")
+	}
+	if n.IsDaemon, err = ReadBool(r); err != nil {
+		return n, errors.Wrapf(err, "failed to read is daemon flag")
+	}
+	if n.IsClient, err = ReadBool(r); err != nil {
+		return n, errors.Wrapf(err, "failed to read is client flag")
+	}
+	if n.ConsistentID, err = ReadObject(r); err != nil {
+		return n, errors.Wrapf(err, "failed to read consistent ID")
+	}
+
+	// read version
+	if n.Version.Major, err = ReadByte(r); err != nil {
+		return n, errors.Wrapf(err, "failed to read version major")
+	}
+	if n.Version.Minor, err = ReadByte(r); err != nil {
+		return n, errors.Wrapf(err, "failed to read version minor")
+	}
+	if n.Version.Maintenance, err = ReadByte(r); err != nil {
+		return n, errors.Wrapf(err, "failed to read version maintenance")
+	}
+	if n.Version.Stage, err = ReadOString(r); err != nil {
+		return n, errors.Wrapf(err, "failed to read version stage")
+	}
+	if n.Version.RevisionTimestamp, err = ReadLong(r); err != nil {
+		return n, errors.Wrapf(err, "failed to read version revision timestamp")
+	}
+	o, err := ReadObject(r)
+	if err != nil {
+		return n, errors.Wrapf(err, "failed to read version revision hash")
+	}
+	if o != nil {
+		var ok bool
+		if n.Version.RevisionHash, ok = o.([]byte); !ok {
+			return n, errors.Errorf("invalid version revision hash type: %T", o)
+		}
+	}
+
+	return n, nil
+}
+
+// readStringCollection reads collection of strings or NULL
+func readStringCollection(r io.Reader) ([]string, error) {
+	o, err := ReadObject(r)
+	if err != nil {
+		return nil, err
+	}
+	if o == nil {
+		return []string{}, nil
+	}
+	c, ok := o.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("invalid collection type: %T", o)
+	}
+	s := make([]string, 0, len(c))
+	for i, v := range c {
+		if v == nil {
+			s = append(s, "")
+			continue
+		}
+		str, ok := v.(string)
+		if !ok {
+			return nil, errors.Errorf("invalid type of collection item with index %d: %T", i, v)
+		}
+		s = append(s, str)
+	}
+	return s, nil
+}
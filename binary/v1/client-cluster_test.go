@@ -0,0 +1,128 @@
+package ignite
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_writeClusterGroupProjection(t *testing.T) {
+	tests := []struct {
+		name    string
+		prj     ClusterGroupProjection
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "all nodes",
+			want: []byte{0},
+		},
+		{
+			name: "server nodes",
+			prj:  ClusterGroupProjection{Role: ClusterNodeRoleServer},
+			want: []byte{1, 1, 0, 0, 0, 2, 0, 1},
+		},
+		{
+			name: "client nodes with attribute",
+			prj:  ClusterGroupProjection{Role: ClusterNodeRoleClient, Attributes: map[string]interface{}{"a": int32(1)}},
+			want: []byte{1, 2, 0, 0, 0,
+				1, 0, 9, 1, 0, 0, 0, 0x61, 3, 1, 0, 0, 0,
+				2, 0, 0},
+		},
+		{
+			name:    "invalid role",
+			prj:     ClusterGroupProjection{Role: 3},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := writeClusterGroupProjection(w, tt.prj)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("writeClusterGroupProjection() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(w.Bytes(), tt.want) {
+				t.Errorf("writeClusterGroupProjection() = %#v, want %#v", w.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_readClusterNodeIDs(t *testing.T) {
+	id := uuid.MustParse("d6589da7-f8b1-4687-b5bd-2ddc7362a4a4")
+	w := &bytes.Buffer{}
+	WriteBool(w, true)
+	WriteLong(w, 10)
+	WriteInt(w, 1)
+	WriteUUID(w, id)
+
+	got, err := readClusterNodeIDs(w)
+	if err != nil {
+		t.Fatalf("readClusterNodeIDs() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []uuid.UUID{id}) {
+		t.Errorf("readClusterNodeIDs() = %v, want %v", got, []uuid.UUID{id})
+	}
+
+	got, err = readClusterNodeIDs(bytes.NewBuffer([]byte{0}))
+	if err != nil {
+		t.Fatalf("readClusterNodeIDs() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("readClusterNodeIDs() = %v, want empty", got)
+	}
+}
+
+func Test_readClusterNode(t *testing.T) {
+	want := ClusterNode{
+		ID:           uuid.MustParse("d6589da7-f8b1-4687-b5bd-2ddc7362a4a4"),
+		Attributes:   map[string]interface{}{"role": "compute"},
+		Addresses:    []string{"127.0.0.1", "10.0.0.1"},
+		HostNames:    []string{"localhost"},
+		Order:        3,
+		IsLocal:      true,
+		IsClient:     false,
+		ConsistentID: "node-1",
+		Version: ClusterNodeVersion{Major: 2, Minor: 9, Maintenance: 1, Stage: "",
+			RevisionTimestamp: 1607516256000, RevisionHash: []byte{1, 2, 3}},
+	}
+
+	writeStrings := func(w *bytes.Buffer, s []string) {
+		WriteByte(w, typeCollection)
+		WriteInt(w, int32(len(s)))
+		WriteByte(w, 1)
+		for _, v := range s {
+			WriteOString(w, v)
+		}
+	}
+	w := &bytes.Buffer{}
+	WriteUUID(w, want.ID)
+	WriteInt(w, 1)
+	WriteOString(w, "role")
+	WriteOString(w, "compute")
+	writeStrings(w, want.Addresses)
+	writeStrings(w, want.HostNames)
+	WriteLong(w, want.Order)
+	WriteBool(w, want.IsLocal)
+	WriteBool(w, want.IsDaemon)
+	WriteBool(w, want.IsClient)
+	WriteOString(w, "node-1")
+	WriteByte(w, 2)
+	WriteByte(w, 9)
+	WriteByte(w, 1)
+	WriteNull(w)
+	WriteLong(w, want.Version.RevisionTimestamp)
+	WriteOArrayBytes(w, want.Version.RevisionHash)
+
+	got, err := readClusterNode(w)
+	if err != nil {
+		t.Fatalf("readClusterNode() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readClusterNode() = %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,112 @@
+package ignite
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+// DefaultHeartbeatInterval is heartbeat interval used if neither
+// ConnInfo.HeartbeatInterval nor server idle timeout is set
+const DefaultHeartbeatInterval = 30 * time.Second
+
+// startHeartbeat starts keep-alive loop.
+// Loop is stopped when connection is closed or heartbeat is failed.
+func (c *client) startHeartbeat(interval time.Duration) error {
+	if c.FeatureSupported(FeatureHeartbeat) {
+		idle, err := c.getIdleTimeout()
+		if err != nil {
+			return err
+		}
+		if idle > 0 && (interval <= 0 || interval > idle/3) {
+			interval = idle / 3
+		}
+	}
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+
+	c.done = make(chan struct{})
+	c.heartbeatStopped = make(chan struct{})
+	go c.connection.heartbeatLoop(interval, c.done, c.heartbeatStopped)
+	return nil
+}
+
+// heartbeatLoop sends heartbeat if connection was idle during the interval.
+// Loop doesn't reference handle of the connection, so connection is closed by finalizer
+// and loop is stopped if client is not closed by user.
+func (c *connection) heartbeatLoop(interval time.Duration, done <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+
+	// heartbeat fails if server doesn't respond during the interval, so connection is marked broken
+	// instead of blocking other requests
+	hb := &client{connection: c, timeout: interval}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-t.C:
+			if now.Sub(time.Unix(0, atomic.LoadInt64(&c.lastUsed))) < interval {
+				continue
+			}
+			if err := hb.heartbeat(); err != nil {
+				select {
+				case <-done:
+					// connection is closed by user
+				default:
+					c.markBroken(errors.Wrapf(err, "heartbeat failed"))
+				}
+				if hb.retry() == nil || c.isClosed() {
+					return
+				}
+				// the next heartbeat reopens connection
+			}
+		}
+	}
+}
+
+// heartbeat sends OP_HEARTBEAT request or cheap OP_CACHE_GET_NAMES request
+// if server does not support heartbeats
+func (c *client) heartbeat() error {
+	code := int16(OpHeartbeat)
+	if !c.FeatureSupported(FeatureHeartbeat) {
+		code = OpCacheGetNames
+	}
+
+	// request and response
+	req := NewRequestOperation(code)
+	res := NewResponseOperation(req.UID)
+
+	// execute operation
+	if err := c.Do(req, res); err != nil {
+		return errors.Wrapf(err, "failed to execute heartbeat operation")
+	}
+
+	return res.CheckStatus()
+}
+
+// getIdleTimeout returns idle timeout configured on server (zero if disabled)
+func (c *client) getIdleTimeout() (time.Duration, error) {
+	// request and response
+	req := NewRequestOperation(OpGetIdleTimeout)
+	res := NewResponseOperation(req.UID)
+
+	// execute operation
+	if err := c.Do(req, res); err != nil {
+		return 0, errors.Wrapf(err, "failed to execute OP_GET_IDLE_TIMEOUT operation")
+	}
+	if err := res.CheckStatus(); err != nil {
+		return 0, err
+	}
+
+	ms, err := ReadLong(res)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read idle timeout")
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
@@ -0,0 +1,201 @@
+package ignite
+
+import (
+	"net"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_client_startHeartbeat(t *testing.T) {
+	var heartbeats int32
+	c := newTestClient(t, ProtocolVersion170, newFeatures(FeatureHeartbeat), func(code int16, payload []byte) (int32, []byte) {
+		switch code {
+		case OpGetIdleTimeout:
+			w := newRequest()
+			WriteLong(&w, 150)
+			return OperationStatusSuccess, w.payload.Bytes()
+		case OpHeartbeat:
+			if atomic.AddInt32(&heartbeats, 1) > 2 {
+				return 1, nil
+			}
+			return OperationStatusSuccess, nil
+		default:
+			return 2, nil
+		}
+	})
+	defer c.Close()
+
+	if err := c.startHeartbeat(time.Minute); err != nil {
+		t.Fatalf("startHeartbeat() error = %v", err)
+	}
+
+	// interval is 50ms (a third of server idle timeout), third heartbeat fails
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Connected() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if c.Connected() {
+		t.Fatalf("Connected() = true after failed heartbeat")
+	}
+	if n := atomic.LoadInt32(&heartbeats); n != 3 {
+		t.Errorf("heartbeat count = %d, want 3", n)
+	}
+	if err := c.Do(NewRequestOperation(OpCacheGetNames), NewResponseOperation(0)); err == nil {
+		t.Errorf("Do() error = nil, want error for broken connection")
+	}
+}
+
+func Test_client_heartbeat(t *testing.T) {
+	c := newTestClient(t, ProtocolVersion110, nil, func(code int16, payload []byte) (int32, []byte) {
+		if code != OpCacheGetNames {
+			return 2, nil
+		}
+		w := newRequest()
+		WriteInt(&w, 0)
+		return OperationStatusSuccess, w.payload.Bytes()
+	})
+	defer c.Close()
+
+	if err := c.heartbeat(); err != nil {
+		t.Errorf("heartbeat() error = %v", err)
+	}
+}
+
+func Test_client_Close_heartbeat(t *testing.T) {
+	c := newTestClient(t, ProtocolVersion170, newFeatures(FeatureHeartbeat), func(code int16, payload []byte) (int32, []byte) {
+		if code == OpGetIdleTimeout {
+			w := newRequest()
+			WriteLong(&w, 3)
+			return OperationStatusSuccess, w.payload.Bytes()
+		}
+		return OperationStatusSuccess, nil
+	})
+
+	if err := c.startHeartbeat(time.Minute); err != nil {
+		t.Fatalf("startHeartbeat() error = %v", err)
+	}
+	// heartbeat is sent every millisecond, deadline is set concurrently
+	time.Sleep(20 * time.Millisecond)
+	go c.SetDeadline(time.Now().Add(time.Minute))
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	select {
+	case <-c.heartbeatStopped:
+	default:
+		t.Errorf("heartbeat loop is running after Close()")
+	}
+	if c.Connected() {
+		t.Errorf("Connected() = true after Close()")
+	}
+}
+
+// closeNotifyConn closes channel when connection is closed
+type closeNotifyConn struct {
+	net.Conn
+	once   sync.Once
+	closed chan struct{}
+}
+
+func (c *closeNotifyConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return c.Conn.Close()
+}
+
+func Test_connectionFinalizer(t *testing.T) {
+	closed := make(chan struct{})
+	dial := newTestDial(newFeatures(FeatureHeartbeat), func(code int16, payload []byte) (int32, []byte) {
+		w := newRequest()
+		if code == OpGetIdleTimeout {
+			WriteLong(&w, 0)
+		}
+		return OperationStatusSuccess, w.payload.Bytes()
+	})
+	connect := func() error {
+		// client with heartbeats and metrics is not closed and becomes unreachable
+		_, err := Connect(ConnInfo{Network: "tcp", Host: "127.0.0.1", Port: 10800, Major: 1, Minor: 7, Patch: 0,
+			Heartbeat: true, HeartbeatInterval: time.Millisecond, Metrics: &testSink{values: map[string]float64{}},
+			Dial: func(network, address string) (net.Conn, error) {
+				conn, err := dial(network, address)
+				return &closeNotifyConn{Conn: conn, closed: closed}, err
+			}})
+		return err
+	}
+	if err := connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		runtime.GC()
+		select {
+		case <-closed:
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	t.Errorf("connection of unreachable client is not closed by finalizer")
+}
+
+// newSilentTestClient creates client connected to the test server which doesn't respond to heartbeats.
+// heartbeats is closed when the first heartbeat is received.
+func newSilentTestClient(t *testing.T, idle int64) (*client, <-chan struct{}) {
+	block := make(chan struct{})
+	t.Cleanup(func() { close(block) })
+	heartbeats := make(chan struct{})
+	var once sync.Once
+	c := newTestClient(t, ProtocolVersion170, newFeatures(FeatureHeartbeat), func(code int16, payload []byte) (int32, []byte) {
+		if code == OpGetIdleTimeout {
+			w := newRequest()
+			WriteLong(&w, idle)
+			return OperationStatusSuccess, w.payload.Bytes()
+		}
+		once.Do(func() { close(heartbeats) })
+		<-block
+		return OperationStatusSuccess, nil
+	})
+	return c, heartbeats
+}
+
+func Test_client_heartbeat_noResponse(t *testing.T) {
+	// interval is 30ms, heartbeat fails if there is no response during the interval
+	c, _ := newSilentTestClient(t, 90)
+	defer c.Close()
+
+	if err := c.startHeartbeat(time.Minute); err != nil {
+		t.Fatalf("startHeartbeat() error = %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Connected() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if c.Connected() {
+		t.Fatalf("Connected() = true after heartbeat without response")
+	}
+}
+
+func Test_client_Close_heartbeatNoResponse(t *testing.T) {
+	// interval is 500ms, so heartbeat deadline is not reached before Close is returned
+	c, heartbeats := newSilentTestClient(t, 1500)
+
+	if err := c.startHeartbeat(time.Minute); err != nil {
+		t.Fatalf("startHeartbeat() error = %v", err)
+	}
+	<-heartbeats
+
+	closed := make(chan error, 1)
+	start := time.Now()
+	go func() { closed <- c.Close() }()
+	select {
+	case <-closed:
+		if d := time.Since(start); d >= 400*time.Millisecond {
+			t.Errorf("Close() took %v, want it to interrupt heartbeat in progress", d)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Close() is blocked by heartbeat without response")
+	}
+}
@@ -0,0 +1,111 @@
+package ignite
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+)
+
+// OperationInfo describes request passed through interceptors.
+// Sizes, Duration and Err are set after invoker returns.
+type OperationInfo struct {
+	// Handshake is true for handshake request
+	Handshake bool
+
+	// OpCode is operation code, zero for handshake
+	OpCode int16
+
+	// CacheID is hash code of the cache name, zero if operation is not related to a cache
+	CacheID int32
+
+	// UID is request ID, zero for handshake
+	UID int64
+
+	// RequestSize is count of bytes sent to server
+	RequestSize int64
+
+	// ResponseSize is count of bytes received from server
+	ResponseSize int64
+
+	// Duration is time from sending the request to receiving the response
+	Duration time.Duration
+
+	// Err is error of sending request or receiving response.
+	// Operation status returned by server is not checked at this level.
+	Err error
+
+	// conn is connection the request is sent over.
+	// Connection is passed with the request, so chain of the interceptors doesn't reference it.
+	conn *connection
+
+	// timeout limits time of the exchange with server, zero means deadline set by SetDeadline is used
+	timeout time.Duration
+
+	// ctx interrupts exchange with server when it is done, nil if exchange is not bound to context
+	ctx context.Context
+}
+
+// Invoker sends request and receives response
+type Invoker func(info *OperationInfo, req Request, res Response) error
+
+// Interceptor is called for every request of the client, including handshake and custom operations.
+// Interceptor must call invoker to continue the chain, or return error to fail the request.
+// Interceptors are configured by ConnInfo.Interceptors, the first one is the outermost.
+type Interceptor func(info *OperationInfo, req Request, res Response, invoker Invoker) error
+
+// operationRequest is implemented by requests based on RequestOperation
+type operationRequest interface {
+	operation() *RequestOperation
+}
+
+// newOperationInfo returns description of the request
+func newOperationInfo(req Request) *OperationInfo {
+	info := &OperationInfo{}
+	switch r := req.(type) {
+	case *RequestHandshake:
+		info.Handshake = true
+	case operationRequest:
+		op := r.operation()
+		info.OpCode = op.Code
+		info.UID = op.UID
+		if opHasCacheID(op.Code) && op.payload.Len() >= 4 {
+			info.CacheID = int32(binary.LittleEndian.Uint32(op.payload.Bytes()))
+		}
+	}
+	return info
+}
+
+// opHasCacheID returns true if request payload of the operation starts with cache ID
+func opHasCacheID(code int16) bool {
+	switch {
+	case code >= OpCacheGet && code <= OpCacheGetSize:
+		return true
+	case code == OpCacheGetConfiguration, code == OpCacheDestroy:
+		return true
+	case code == OpQueryScan, code == OpQuerySQL, code == OpQuerySQLFields:
+		return true
+	}
+	return false
+}
+
+// invokeDo is the final invoker of the chain, it sends request over the connection of the operation
+func invokeDo(info *OperationInfo, req Request, res Response) error {
+	return info.conn.do(info, req, res)
+}
+
+// invokeExchange is the final invoker of the chain if connection mutex is locked by caller
+func invokeExchange(info *OperationInfo, req Request, res Response) error {
+	return info.conn.exchange(info, req, res)
+}
+
+// chainInterceptors returns invoker which calls interceptors before the final invoker
+func chainInterceptors(interceptors []Interceptor, final Invoker) Invoker {
+	invoker := final
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		next, interceptor := invoker, interceptors[i]
+		invoker = func(info *OperationInfo, req Request, res Response) error {
+			return interceptor(info, req, res, next)
+		}
+	}
+	return invoker
+}
@@ -0,0 +1,105 @@
+package ignite
+
+import (
+	"testing"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+func TestConnect_Interceptors(t *testing.T) {
+	h := func(code int16, payload []byte) (int32, []byte) {
+		if code != OpCacheGet {
+			return 1, nil
+		}
+		w := newRequest()
+		WriteOString(&w, "value")
+		return OperationStatusSuccess, w.payload.Bytes()
+	}
+
+	var calls []string
+	var infos []OperationInfo
+	outer := func(info *OperationInfo, req Request, res Response, invoker Invoker) error {
+		calls = append(calls, "outer")
+		if info.OpCode == OpCacheDestroy {
+			return errors.Errorf("OP_CACHE_DESTROY is forbidden")
+		}
+		err := invoker(info, req, res)
+		infos = append(infos, *info)
+		return err
+	}
+	inner := func(info *OperationInfo, req Request, res Response, invoker Invoker) error {
+		calls = append(calls, "inner")
+		return invoker(info, req, res)
+	}
+
+	c, err := Connect(ConnInfo{Network: "tcp", Host: "127.0.0.1", Port: 10800, Major: 1, Minor: 1, Patch: 0,
+		Dial: newTestDial(nil, h), Interceptors: []Interceptor{outer, inner}})
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer c.Close()
+
+	if _, err = c.CacheGet("TestCache", false, "key"); err != nil {
+		t.Fatalf("CacheGet() error = %v", err)
+	}
+	if err = c.CacheDestroy("TestCache"); err == nil {
+		t.Errorf("CacheDestroy() error = nil, want error of interceptor")
+	}
+
+	wantCalls := []string{"outer", "inner", "outer", "inner", "outer"}
+	if len(calls) != len(wantCalls) {
+		t.Fatalf("interceptor calls = %v, want %v", calls, wantCalls)
+	}
+	for i := range calls {
+		if calls[i] != wantCalls[i] {
+			t.Fatalf("interceptor calls = %v, want %v", calls, wantCalls)
+		}
+	}
+
+	if len(infos) != 2 {
+		t.Fatalf("got %d operation infos, want 2", len(infos))
+	}
+	if hs := infos[0]; !hs.Handshake || hs.OpCode != 0 || hs.RequestSize == 0 || hs.ResponseSize == 0 || hs.Err != nil {
+		t.Errorf("handshake info = %+v", hs)
+	}
+	get := infos[1]
+	if get.Handshake || get.OpCode != OpCacheGet || get.CacheID != HashCode("TestCache") || get.UID == 0 ||
+		get.RequestSize == 0 || get.ResponseSize == 0 || get.Duration <= 0 || get.Err != nil {
+		t.Errorf("OP_CACHE_GET info = %+v", get)
+	}
+}
+
+func Test_newOperationInfo(t *testing.T) {
+	put := NewRequestOperation(OpCachePut)
+	WriteInt(put, HashCode("TestCache"))
+	create := NewRequestCacheCreateWithConfiguration(OpCacheCreateWithConfiguration)
+
+	tests := []struct {
+		name string
+		req  Request
+		want OperationInfo
+	}{
+		{
+			name: "1",
+			req:  NewRequestHandshake(1, 1, 0, "", ""),
+			want: OperationInfo{Handshake: true},
+		},
+		{
+			name: "2",
+			req:  put,
+			want: OperationInfo{OpCode: OpCachePut, UID: put.UID, CacheID: HashCode("TestCache")},
+		},
+		{
+			name: "3",
+			req:  create,
+			want: OperationInfo{OpCode: OpCacheCreateWithConfiguration, UID: create.UID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newOperationInfo(tt.req); *got != tt.want {
+				t.Errorf("newOperationInfo() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package ignite
 
 import (
+	"io"
+
 	"github.com/amsokol/ignite-go-client/binary/errors"
 )
 
@@ -15,10 +17,33 @@ const (
 	PeekModeBackup = 3
 )
 
+const (
+	cacheFlagKeepBinary       = 1
+	cacheFlagWithExpiryPolicy = 4
+)
+
 // Key-Value Queries
 // See for details:
 // https://apacheignite.readme.io/docs/binary-client-protocol-key-value-operations
 
+// writeCacheFlags writes cache operation flags followed by expiry policy of the client view (if any)
+func (c *client) writeCacheFlags(w io.Writer, binary bool) error {
+	var flags byte
+	if binary {
+		flags |= cacheFlagKeepBinary
+	}
+	if c.expiryPolicy == nil {
+		return WriteByte(w, flags)
+	}
+	if !c.state().version.AtLeast(ProtocolVersion160) {
+		return errors.Errorf("expiry policy is not supported by protocol %s, v1.6.0+ is required", c.state().version)
+	}
+	if err := WriteByte(w, flags|cacheFlagWithExpiryPolicy); err != nil {
+		return err
+	}
+	return writeExpiryPolicy(w, *c.expiryPolicy)
+}
+
 // CacheGet retrieves a value from cache by key.
 func (c *client) CacheGet(cache string, binary bool, key interface{}) (interface{}, error) {
 	// request and response
@@ -29,8 +54,8 @@ func (c *client) CacheGet(cache string, binary bool, key interface{}) (interface
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return nil, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return nil, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return nil, errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteObject(req, key); err != nil {
 		return nil, errors.Wrapf(err, "failed to write cache key")
@@ -57,8 +82,8 @@ func (c *client) CacheGetAll(cache string, binary bool, keys []interface{}) (map
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return nil, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return nil, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return nil, errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteInt(req, int32(len(keys))); err != nil {
 		return nil, errors.Wrapf(err, "failed to write key count")
@@ -108,8 +133,8 @@ func (c *client) CachePut(cache string, binary bool, key interface{}, value inte
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteObject(req, key); err != nil {
 		return errors.Wrapf(err, "failed to write cache key")
@@ -136,8 +161,8 @@ func (c *client) CachePutAll(cache string, binary bool, data map[interface{}]int
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteInt(req, int32(len(data))); err != nil {
 		return errors.Wrapf(err, "failed to write key count")
@@ -169,8 +194,8 @@ func (c *client) CacheContainsKey(cache string, binary bool, key interface{}) (b
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return false, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return false, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return false, errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteObject(req, key); err != nil {
 		return false, errors.Wrapf(err, "failed to write cache key")
@@ -197,8 +222,8 @@ func (c *client) CacheContainsKeys(cache string, binary bool, keys []interface{}
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return false, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return false, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return false, errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteInt(req, int32(len(keys))); err != nil {
 		return false, errors.Wrapf(err, "failed to write key count")
@@ -230,8 +255,8 @@ func (c *client) CacheGetAndPut(cache string, binary bool, key interface{}, valu
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return nil, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return nil, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return nil, errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteObject(req, key); err != nil {
 		return nil, errors.Wrapf(err, "failed to write cache key")
@@ -262,8 +287,8 @@ func (c *client) CacheGetAndReplace(cache string, binary bool, key interface{},
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return nil, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return nil, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return nil, errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteObject(req, key); err != nil {
 		return nil, errors.Wrapf(err, "failed to write cache key")
@@ -293,8 +318,8 @@ func (c *client) CacheGetAndRemove(cache string, binary bool, key interface{}) (
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return nil, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return nil, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return nil, errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteObject(req, key); err != nil {
 		return nil, errors.Wrapf(err, "failed to write cache key")
@@ -321,8 +346,8 @@ func (c *client) CachePutIfAbsent(cache string, binary bool, key interface{}, va
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return false, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return false, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return false, errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteObject(req, key); err != nil {
 		return false, errors.Wrapf(err, "failed to write cache key")
@@ -352,8 +377,8 @@ func (c *client) CacheGetAndPutIfAbsent(cache string, binary bool, key interface
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return nil, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return nil, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return nil, errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteObject(req, key); err != nil {
 		return nil, errors.Wrapf(err, "failed to write cache key")
@@ -383,8 +408,8 @@ func (c *client) CacheReplace(cache string, binary bool, key interface{}, value
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return false, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return false, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return false, errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteObject(req, key); err != nil {
 		return false, errors.Wrapf(err, "failed to write cache key")
@@ -415,8 +440,8 @@ func (c *client) CacheReplaceIfEquals(cache string, binary bool, key interface{}
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return false, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return false, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return false, errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteObject(req, key); err != nil {
 		return false, errors.Wrapf(err, "failed to write cache key")
@@ -449,8 +474,8 @@ func (c *client) CacheClear(cache string, binary bool) error {
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return errors.Wrapf(err, "failed to write cache flags")
 	}
 
 	// execute operation
@@ -470,8 +495,8 @@ func (c *client) CacheClearKey(cache string, binary bool, key interface{}) error
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteObject(req, key); err != nil {
 		return errors.Wrapf(err, "failed to write cache key")
@@ -494,8 +519,8 @@ func (c *client) CacheClearKeys(cache string, binary bool, keys []interface{}) e
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteInt(req, int32(len(keys))); err != nil {
 		return errors.Wrapf(err, "failed to write key count")
@@ -524,8 +549,8 @@ func (c *client) CacheRemoveKey(cache string, binary bool, key interface{}) (boo
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return false, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return false, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return false, errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteObject(req, key); err != nil {
 		return false, errors.Wrapf(err, "failed to write cache key")
@@ -553,8 +578,8 @@ func (c *client) CacheRemoveIfEquals(cache string, binary bool, key interface{},
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return false, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return false, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return false, errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteObject(req, key); err != nil {
 		return false, errors.Wrapf(err, "failed to write cache key")
@@ -584,15 +609,15 @@ func (c *client) CacheGetSize(cache string, binary bool, modes []byte) (int64, e
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return 0, errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return 0, errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return 0, errors.Wrapf(err, "failed to write cache flags")
 	}
 	var count int32
-	if modes != nil || len(modes) > 0 {
+	if len(modes) > 0 {
 		count = int32(len(modes))
 	}
 	if err := WriteInt(req, count); err != nil {
-		return 0, errors.Wrapf(err, "failed to write binary flag")
+		return 0, errors.Wrapf(err, "failed to write peek mode count")
 	}
 	if count > 0 {
 		for i, m := range modes {
@@ -623,8 +648,8 @@ func (c *client) CacheRemoveKeys(cache string, binary bool, keys []interface{})
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return errors.Wrapf(err, "failed to write cache flags")
 	}
 	if err := WriteInt(req, int32(len(keys))); err != nil {
 		return errors.Wrapf(err, "failed to write key count")
@@ -653,8 +678,8 @@ func (c *client) CacheRemoveAll(cache string, binary bool) error {
 	if err := WriteInt(req, HashCode(cache)); err != nil {
 		return errors.Wrapf(err, "failed to write cache name")
 	}
-	if err := WriteBool(req, binary); err != nil {
-		return errors.Wrapf(err, "failed to write binary flag")
+	if err := c.writeCacheFlags(req, binary); err != nil {
+		return errors.Wrapf(err, "failed to write cache flags")
 	}
 
 	// execute operation
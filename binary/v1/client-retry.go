@@ -0,0 +1,283 @@
+package ignite
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+const (
+	// DefaultRetryMaxAttempts is count of attempts used if RetryPolicy.MaxAttempts is not set
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryBackoff is delay before the first retry used if RetryPolicy.Backoff is not set
+	DefaultRetryBackoff = 100 * time.Millisecond
+	// DefaultRetryMaxBackoff is delay limit used if RetryPolicy.MaxBackoff is not set
+	DefaultRetryMaxBackoff = 5 * time.Second
+
+	// DefaultCircuitBreakerFailureThreshold is failure count used if CircuitBreaker.FailureThreshold is not set
+	DefaultCircuitBreakerFailureThreshold = 5
+	// DefaultCircuitBreakerOpenTimeout is open state duration used if CircuitBreaker.OpenTimeout is not set
+	DefaultCircuitBreakerOpenTimeout = 30 * time.Second
+)
+
+// RetryPolicy describes how failed requests are retried.
+// Only idempotent operations (see OpIdempotent) are retried.
+// Broken connection is reopened (including handshake) before the next attempt of any operation.
+type RetryPolicy struct {
+	// MaxAttempts is count of attempts including the first one, DefaultRetryMaxAttempts if zero.
+	// Set 1 to disable retries.
+	MaxAttempts int
+
+	// Backoff is delay before the first retry, DefaultRetryBackoff if zero.
+	// Delay is doubled for every next retry.
+	Backoff time.Duration
+
+	// MaxBackoff is delay limit, DefaultRetryMaxBackoff if zero
+	MaxBackoff time.Duration
+
+	// Retryable returns true if request failed with error can be retried.
+	// If nil, requests failed with ErrConnectionLost are retried.
+	Retryable func(err error) bool
+
+	// Operations overrides classification of the operations by op code:
+	// true to retry operation (e.g. custom idempotent operation), false to never retry it
+	Operations map[int16]bool
+}
+
+// CircuitBreaker describes when client fails fast with ErrCircuitOpen without sending requests to server.
+// Circuit is opened after FailureThreshold consecutive requests failed with ErrConnectionLost.
+// After OpenTimeout one trial request is sent, circuit is closed if it succeeds or opened again otherwise.
+type CircuitBreaker struct {
+	// FailureThreshold is count of consecutive failures to open circuit,
+	// DefaultCircuitBreakerFailureThreshold if zero
+	FailureThreshold int
+
+	// OpenTimeout is time to reject requests after circuit is opened,
+	// DefaultCircuitBreakerOpenTimeout if zero
+	OpenTimeout time.Duration
+}
+
+// OpIdempotent returns true if operation can be safely sent again, e.g. reads and OP_CACHE_GET_OR_CREATE_*.
+// Operations changing data (OP_CACHE_GET_AND_PUT, OP_CACHE_REPLACE, OP_CACHE_REMOVE_*, etc.),
+// OP_QUERY_SQL_FIELDS (may execute DML) and cursor operations (cursor is lost with connection) are not idempotent.
+func OpIdempotent(code int16) bool {
+	switch code {
+	case OpHeartbeat, OpGetIdleTimeout,
+		OpCacheGetNames, OpCacheGetConfiguration,
+		OpCacheGetOrCreateWithName, OpCacheGetOrCreateWithConfiguration,
+		OpCacheGet, OpCacheGetAll, OpCacheContainsKey, OpCacheContainsKeys, OpCacheGetSize,
+		OpQuerySQL, OpQueryScan,
+		OpClusterGroupGetNodeIDs, OpClusterGroupGetNodeInfo, OpServiceGetDescriptors:
+		return true
+	}
+	return false
+}
+
+// WithRetryPolicy returns view of the client which uses the policy instead of ConnInfo.Retry.
+// View shares connection with the client, so closing either of them closes both.
+func (c *client) WithRetryPolicy(p RetryPolicy) Client {
+	return &client{connection: c.connection, handle: c.handle, expiryPolicy: c.expiryPolicy, retryPolicy: &p}
+}
+
+// retry returns retry policy of the client, nil if retries are disabled
+func (c *client) retry() *RetryPolicy {
+	if c.retryPolicy != nil {
+		return c.retryPolicy
+	}
+	return c.connection.retryPolicy
+}
+
+// canRetry returns true if the request can be sent again
+func (p *RetryPolicy) canRetry(req Request, err error) bool {
+	r, ok := req.(operationRequest)
+	if !ok {
+		return false
+	}
+	code := r.operation().Code
+	if v, ok := p.Operations[code]; ok {
+		if !v {
+			return false
+		}
+	} else if !OpIdempotent(code) {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return errors.Is(err, errors.ErrConnectionLost)
+}
+
+// maxAttempts returns count of attempts
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts > 0 {
+		return p.MaxAttempts
+	}
+	return DefaultRetryMaxAttempts
+}
+
+// backoff returns delay before the retry with number n (starting from 1)
+func (p *RetryPolicy) backoff(n int) time.Duration {
+	d, max := p.Backoff, p.MaxBackoff
+	if d <= 0 {
+		d = DefaultRetryBackoff
+	}
+	if max <= 0 {
+		max = DefaultRetryMaxBackoff
+	}
+	for i := 1; i < n && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// doWithRetry sends request and retries it according to the policy
+func (c *client) doWithRetry(p *RetryPolicy, req Request, res Response) error {
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			time.Sleep(p.backoff(attempt - 1))
+		}
+		err := c.attempt(req, res, true)
+		if err == nil || attempt >= p.maxAttempts() || !p.canRetry(req, err) || c.isClosed() {
+			return err
+		}
+	}
+}
+
+// attempt sends request once. If reconnect is true, broken connection is reopened before.
+func (c *client) attempt(req Request, res Response, reconnect bool) (err error) {
+	if b := c.breaker; b != nil {
+		if err := b.allow(); err != nil {
+			return err
+		}
+		defer func() { b.record(err) }()
+	}
+	if reconnect && c.brokenErr() != nil {
+		if err := c.reconnect(); err != nil {
+			return err
+		}
+	}
+	info := newOperationInfo(req)
+	info.conn = c.connection
+	info.timeout = c.timeout
+	info.ctx = c.ctx
+	if c.invoker != nil {
+		return c.invoker(info, req, res)
+	}
+	return c.do(info, req, res)
+}
+
+// reconnect reopens broken connection and makes handshake
+func (c *connection) reconnect() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.isClosed() {
+		return &errors.ConnectionError{Err: errors.Errorf("connection is closed")}
+	}
+	if c.brokenErr() == nil {
+		// reopened by another request
+		return nil
+	}
+
+	conn, _, err := dial(c.ci)
+	if err != nil {
+		return err
+	}
+	if old := c.swapNetConn(conn); old != nil {
+		old.Close()
+	}
+	if c.isClosed() {
+		// closed by user while connection was reopened
+		if conn = c.swapNetConn(nil); conn != nil {
+			conn.Close()
+		}
+		return &errors.ConnectionError{Err: errors.Errorf("connection is closed")}
+	}
+	c.broken.Store(brokenError{})
+	version := ProtocolVersion{Major: c.ci.Major, Minor: c.ci.Minor, Patch: c.ci.Patch}
+	c.setState(version, nil)
+	if c.metrics != nil {
+		// server cursors are lost with connection
+		if n := atomic.SwapInt64(&c.cursors, 0); n > 0 {
+			c.metrics.AddGauge(MetricOpenCursors, nil, -float64(n))
+		}
+	}
+
+	// make handshake, mutex is already locked so interceptors are called with exchange as final invoker
+	req := NewRequestHandshake(c.ci.Major, c.ci.Minor, c.ci.Patch, c.ci.Username, c.ci.Password)
+	res := NewResponseHandshake(c.ci.Major, c.ci.Minor, c.ci.Patch)
+	info := newOperationInfo(req)
+	info.conn = c
+	if err = chainInterceptors(c.interceptors, invokeExchange)(info, req, res); err != nil {
+		c.markBroken(err)
+		return errors.Wrapf(err, "failed to make handshake")
+	}
+	if !res.Success {
+		err = &errors.HandshakeError{Status: res.Status, Message: res.Message,
+			Major: res.Major, Minor: res.Minor, Patch: res.Patch}
+		c.markBroken(&errors.ConnectionError{Err: err})
+		return err
+	}
+	c.setState(version, res.Features)
+	return nil
+}
+
+// circuitBreaker tracks consecutive failures of the connection
+type circuitBreaker struct {
+	threshold int
+	timeout   time.Duration
+
+	mutex    sync.Mutex
+	failures int
+	openedAt time.Time
+	// trial is true when request is sent to check if circuit can be closed
+	trial bool
+}
+
+func newCircuitBreaker(cb CircuitBreaker) *circuitBreaker {
+	b := &circuitBreaker{threshold: cb.FailureThreshold, timeout: cb.OpenTimeout}
+	if b.threshold <= 0 {
+		b.threshold = DefaultCircuitBreakerFailureThreshold
+	}
+	if b.timeout <= 0 {
+		b.timeout = DefaultCircuitBreakerOpenTimeout
+	}
+	return b
+}
+
+// allow returns error if request must be rejected
+func (b *circuitBreaker) allow() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+	if b.trial || time.Since(b.openedAt) < b.timeout {
+		return errors.Wrapf(errors.ErrCircuitOpen, "%d requests failed in a row, retry after %s",
+			b.failures, b.openedAt.Add(b.timeout).Format(time.RFC3339))
+	}
+	b.trial = true
+	return nil
+}
+
+// record counts result of the allowed request
+func (b *circuitBreaker) record(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.trial = false
+	if err == nil || !errors.Is(err, errors.ErrConnectionLost) {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
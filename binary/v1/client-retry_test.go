@@ -0,0 +1,231 @@
+package ignite
+
+import (
+	"bytes"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+// newRestartingDial returns dial function which opens connections closed by server after handshake
+// (like restarting node) for the first drops dials, fails dial while down is true and opens working connections after that
+func newRestartingDial(drops int32, down *int32, dials *int32, h testHandler) func(network, address string) (net.Conn, error) {
+	dropping := newTestServerDial(func(sc net.Conn) {
+		res := &bytes.Buffer{}
+		WriteBool(res, true)
+		WriteInt(sc, int32(res.Len()))
+		sc.Write(res.Bytes())
+	})
+	working := newTestDial(nil, h)
+	return func(network, address string) (net.Conn, error) {
+		n := atomic.AddInt32(dials, 1)
+		if down != nil && atomic.LoadInt32(down) == 1 {
+			return nil, errors.Errorf("connection refused")
+		}
+		if n <= drops {
+			return dropping(network, address)
+		}
+		return working(network, address)
+	}
+}
+
+func TestConnect_Retry(t *testing.T) {
+	h := func(code int16, payload []byte) (int32, []byte) {
+		w := newRequest()
+		if code == OpCacheGet {
+			WriteOString(&w, "value")
+		}
+		return OperationStatusSuccess, w.payload.Bytes()
+	}
+	var dials int32
+	c, err := Connect(ConnInfo{Network: "tcp", Host: "127.0.0.1", Port: 10800, Major: 1, Minor: 1, Patch: 0,
+		Dial: newRestartingDial(2, nil, &dials, h), Retry: &RetryPolicy{Backoff: time.Millisecond}})
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer c.Close()
+
+	// connection is lost, idempotent operation is retried with new connection (which is lost too)
+	// and then with the third one
+	if v, err := c.CacheGet("TestCache", false, "key"); err != nil || v != "value" {
+		t.Fatalf("CacheGet() = %v, %v, want \"value\"", v, err)
+	}
+	if n := atomic.LoadInt32(&dials); n != 3 {
+		t.Errorf("dial count = %d, want 3", n)
+	}
+	if !c.Connected() {
+		t.Errorf("Connected() = false after reconnect")
+	}
+
+	// retries are disabled by the view
+	dials = 0
+	c, err = Connect(ConnInfo{Network: "tcp", Host: "127.0.0.1", Port: 10800, Major: 1, Minor: 1, Patch: 0,
+		Dial: newRestartingDial(1, nil, &dials, h), Retry: &RetryPolicy{Backoff: time.Millisecond}})
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer c.Close()
+	if _, err = c.WithRetryPolicy(RetryPolicy{MaxAttempts: 1}).CacheGet("TestCache", false, "key"); !errors.Is(err, ErrConnectionLost) {
+		t.Errorf("CacheGet() error = %v, want ErrConnectionLost", err)
+	}
+	// broken connection is reopened before the next request
+	if err = c.CachePut("TestCache", false, "key", "value"); err != nil {
+		t.Errorf("CachePut() error = %v", err)
+	}
+}
+
+func Test_connection_reconnect_race(t *testing.T) {
+	ci := ConnInfo{Network: "tcp", Host: "127.0.0.1", Port: 10800, Major: 1, Minor: 7, Patch: 0,
+		Dial: newTestDial(newFeatures(FeatureHeartbeat), func(code int16, payload []byte) (int32, []byte) {
+			return OperationStatusSuccess, nil
+		}), Retry: &RetryPolicy{Backoff: time.Millisecond}}
+	cl, err := Connect(ci)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer cl.Close()
+	c := cl.(*client)
+
+	// features are queried while connection is reopened
+	stop := make(chan struct{})
+	queried := make(chan struct{})
+	go func() {
+		defer close(queried)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				c.FeatureSupported(FeatureHeartbeat)
+				c.ProtocolVersion()
+			}
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		c.markBroken(errors.Errorf("test error"))
+		if err := c.reconnect(); err != nil {
+			t.Fatalf("reconnect() error = %v", err)
+		}
+	}
+	close(stop)
+	<-queried
+
+	if !c.FeatureSupported(FeatureHeartbeat) || c.ProtocolVersion() != ProtocolVersion170 {
+		t.Errorf("FeatureSupported() = false or ProtocolVersion() = %s after reconnect", c.ProtocolVersion())
+	}
+}
+
+func TestConnect_RetryNotIdempotent(t *testing.T) {
+	h := func(code int16, payload []byte) (int32, []byte) {
+		return OperationStatusSuccess, nil
+	}
+	var dials int32
+	c, err := Connect(ConnInfo{Network: "tcp", Host: "127.0.0.1", Port: 10800, Major: 1, Minor: 1, Patch: 0,
+		Dial: newRestartingDial(1, nil, &dials, h), Retry: &RetryPolicy{Backoff: time.Millisecond}})
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer c.Close()
+
+	if err = c.CachePut("TestCache", false, "key", "value"); !errors.Is(err, ErrConnectionLost) {
+		t.Errorf("CachePut() error = %v, want ErrConnectionLost", err)
+	}
+	if n := atomic.LoadInt32(&dials); n != 1 {
+		t.Errorf("dial count = %d, want 1", n)
+	}
+}
+
+func TestConnect_CircuitBreaker(t *testing.T) {
+	h := func(code int16, payload []byte) (int32, []byte) {
+		w := newRequest()
+		WriteInt(&w, 0)
+		return OperationStatusSuccess, w.payload.Bytes()
+	}
+	var dials, down int32
+	c, err := Connect(ConnInfo{Network: "tcp", Host: "127.0.0.1", Port: 10800, Major: 1, Minor: 1, Patch: 0,
+		Dial:           newRestartingDial(1, &down, &dials, h),
+		Retry:          &RetryPolicy{MaxAttempts: 1},
+		CircuitBreaker: &CircuitBreaker{FailureThreshold: 2, OpenTimeout: 50 * time.Millisecond}})
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer c.Close()
+	atomic.StoreInt32(&down, 1)
+
+	// connection is lost, then reconnect fails
+	for i := 0; i < 2; i++ {
+		if _, err = c.CacheGetNames(); !errors.Is(err, ErrConnectionLost) {
+			t.Fatalf("CacheGetNames() error = %v, want ErrConnectionLost", err)
+		}
+	}
+	n := atomic.LoadInt32(&dials)
+	if _, err = c.CacheGetNames(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("CacheGetNames() error = %v, want ErrCircuitOpen", err)
+	}
+	if atomic.LoadInt32(&dials) != n {
+		t.Errorf("connection is reopened while circuit is open")
+	}
+
+	atomic.StoreInt32(&down, 0)
+	time.Sleep(60 * time.Millisecond)
+	if _, err = c.CacheGetNames(); err != nil {
+		t.Errorf("CacheGetNames() error = %v after circuit open timeout", err)
+	}
+	if _, err = c.CacheGetNames(); err != nil {
+		t.Errorf("CacheGetNames() error = %v after circuit is closed", err)
+	}
+}
+
+func TestRetryPolicy_canRetry(t *testing.T) {
+	lost := &errors.ConnectionError{Err: errors.Errorf("EOF")}
+	tests := []struct {
+		name string
+		p    RetryPolicy
+		req  Request
+		err  error
+		want bool
+	}{
+		{name: "1", req: NewRequestOperation(OpCacheGet), err: lost, want: true},
+		{name: "2", req: NewRequestOperation(OpCacheGetOrCreateWithName), err: lost, want: true},
+		{name: "3", req: NewRequestOperation(OpCacheGetAndPut), err: lost, want: false},
+		{name: "4", req: NewRequestOperation(OpCacheGet), err: errors.Errorf("failed"), want: false},
+		{name: "5", req: NewRequestHandshake(1, 1, 0, "", ""), err: lost, want: false},
+		{name: "6", p: RetryPolicy{Operations: map[int16]bool{OpCacheGet: false}},
+			req: NewRequestOperation(OpCacheGet), err: lost, want: false},
+		{name: "7", p: RetryPolicy{Operations: map[int16]bool{OpCachePut: true}},
+			req: NewRequestOperation(OpCachePut), err: lost, want: true},
+		{name: "8", p: RetryPolicy{Retryable: func(err error) bool { return true }},
+			req: NewRequestOperation(OpCacheGet), err: errors.Errorf("failed"), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.canRetry(tt.req, tt.err); got != tt.want {
+				t.Errorf("RetryPolicy.canRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	tests := []struct {
+		name string
+		p    RetryPolicy
+		n    int
+		want time.Duration
+	}{
+		{name: "1", n: 1, want: DefaultRetryBackoff},
+		{name: "2", n: 3, want: 4 * DefaultRetryBackoff},
+		{name: "3", p: RetryPolicy{Backoff: time.Second, MaxBackoff: 3 * time.Second}, n: 2, want: 2 * time.Second},
+		{name: "4", p: RetryPolicy{Backoff: time.Second, MaxBackoff: 3 * time.Second}, n: 10, want: 3 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.backoff(tt.n); got != tt.want {
+				t.Errorf("RetryPolicy.backoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
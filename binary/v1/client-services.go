@@ -0,0 +1,239 @@
+package ignite
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+// Services
+// See for details:
+// https://ignite.apache.org/docs/latest/binary-client-protocol/binary-client-protocol#services
+
+const (
+	serviceInvokeFlagKeepBinary = 1
+)
+
+const (
+	// ServicePlatformJava is Java service
+	ServicePlatformJava = 0
+	// ServicePlatformDotNet is .NET service
+	ServicePlatformDotNet = 1
+)
+
+// ServiceInvokeOptions contains options of ServiceInvoke func
+type ServiceInvokeOptions struct {
+	// Timeout of the service call. Zero value means no timeout,
+	// but context deadline is used if context has one.
+	Timeout time.Duration
+
+	// KeepBinary keeps complex objects of arguments and result in binary form on server side.
+	KeepBinary bool
+
+	// NodeIDs limits the nodes the service may be invoked on.
+	// Empty value means any node where the service is deployed.
+	NodeIDs []uuid.UUID
+
+	// Nodes limits the nodes the service may be invoked on by cluster group projection.
+	// Node IDs are resolved with OP_CLUSTER_GROUP_GET_NODE_IDS and appended to NodeIDs.
+	Nodes *ClusterGroupProjection
+}
+
+// ServiceDescriptor describes deployed service
+type ServiceDescriptor struct {
+	// Service name
+	Name string
+
+	// Service class name
+	ServiceClass string
+
+	// Maximum allowed total number of deployed services in the grid, 0 for unlimited
+	TotalCount int32
+
+	// Maximum allowed number of deployed services on each node, 0 for unlimited
+	MaxPerNodeCount int32
+
+	// Cache name used for key-to-node affinity calculation, empty if not specified
+	CacheName string
+
+	// ID of grid node that initiated the service deployment
+	OriginNodeID uuid.UUID
+
+	// Service platform.
+	// ServicePlatformJava = 0
+	// ServicePlatformDotNet = 1
+	Platform byte
+}
+
+// ServiceInvoke invokes method of the service deployed in the cluster.
+func (c *client) ServiceInvoke(ctx context.Context, name string, method string, args []interface{},
+	opts ServiceInvokeOptions) (interface{}, error) {
+	if !c.FeatureSupported(FeatureServiceInvoke) {
+		return nil, errors.Errorf("OP_SERVICE_INVOKE operation is not supported by server (protocol %s)", c.state().version)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	timeout := opts.Timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := time.Until(deadline); timeout == 0 || left < timeout {
+			timeout = left
+		}
+		if timeout <= 0 {
+			// deadline is passed, context is done
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+	}
+
+	// requests are interrupted when context is done
+	cc := &client{connection: c.connection, handle: c.handle, expiryPolicy: c.expiryPolicy,
+		retryPolicy: c.retryPolicy, timeout: c.timeout, ctx: ctx}
+
+	nodeIDs := opts.NodeIDs
+	if opts.Nodes != nil {
+		ids, err := cc.ClusterGroupGetNodeIDs(*opts.Nodes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get node IDs of the cluster group")
+		}
+		if len(ids) == 0 {
+			return nil, errors.Errorf("there are no nodes in the cluster group to invoke service \"%s\"", name)
+		}
+		nodeIDs = append(append([]uuid.UUID{}, nodeIDs...), ids...)
+	}
+
+	// request and response
+	req := NewRequestOperation(OpServiceInvoke)
+	res := NewResponseOperation(req.UID)
+
+	// set parameters
+	if err := writeServiceInvoke(req, name, method, args, opts.KeepBinary, timeout, nodeIDs); err != nil {
+		return nil, err
+	}
+
+	// execute operation
+	if err := cc.Do(req, res); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, errors.Wrapf(err, "failed to execute OP_SERVICE_INVOKE operation")
+	}
+	if err := res.CheckStatus(); err != nil {
+		return nil, err
+	}
+
+	return ReadObject(res)
+}
+
+// ServiceGetDescriptors returns descriptors of all services deployed in the cluster.
+func (c *client) ServiceGetDescriptors() ([]ServiceDescriptor, error) {
+	if !c.FeatureSupported(FeatureGetServiceDescriptors) {
+		return nil, errors.Errorf("OP_SERVICE_GET_DESCRIPTORS operation is not supported by server (protocol %s)", c.state().version)
+	}
+
+	// request and response
+	req := NewRequestOperation(OpServiceGetDescriptors)
+	res := NewResponseOperation(req.UID)
+
+	// execute operation
+	if err := c.Do(req, res); err != nil {
+		return nil, errors.Wrapf(err, "failed to execute OP_SERVICE_GET_DESCRIPTORS operation")
+	}
+	if err := res.CheckStatus(); err != nil {
+		return nil, err
+	}
+
+	// read response data
+	count, err := readLength(res)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read service descriptor count")
+	}
+	ds := make([]ServiceDescriptor, 0, int(count))
+	for i := 0; i < int(count); i++ {
+		d, err := readServiceDescriptor(res)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read service descriptor with index %d", i)
+		}
+		ds = append(ds, d)
+	}
+
+	return ds, nil
+}
+
+// writeServiceInvoke writes parameters of OP_SERVICE_INVOKE request
+func writeServiceInvoke(w io.Writer, name string, method string, args []interface{},
+	keepBinary bool, timeout time.Duration, nodeIDs []uuid.UUID) error {
+	if err := WriteOString(w, name); err != nil {
+		return errors.Wrapf(err, "failed to write service name")
+	}
+	var flags byte
+	if keepBinary {
+		flags |= serviceInvokeFlagKeepBinary
+	}
+	if err := WriteByte(w, flags); err != nil {
+		return errors.Wrapf(err, "failed to write flags")
+	}
+	// timeout is rounded up, so sub-millisecond timeout doesn't mean no timeout
+	if err := WriteLong(w, int64((timeout+time.Millisecond-1)/time.Millisecond)); err != nil {
+		return errors.Wrapf(err, "failed to write timeout")
+	}
+	if err := WriteInt(w, int32(len(nodeIDs))); err != nil {
+		return errors.Wrapf(err, "failed to write node ID count")
+	}
+	for i, id := range nodeIDs {
+		if err := WriteUUID(w, id); err != nil {
+			return errors.Wrapf(err, "failed to write node ID with index %d", i)
+		}
+	}
+	if err := WriteOString(w, method); err != nil {
+		return errors.Wrapf(err, "failed to write method name")
+	}
+	if err := WriteInt(w, int32(len(args))); err != nil {
+		return errors.Wrapf(err, "failed to write argument count")
+	}
+	for i, v := range args {
+		if err := WriteObject(w, v); err != nil {
+			return errors.Wrapf(err, "failed to write argument with index %d", i)
+		}
+	}
+	return nil
+}
+
+// readServiceDescriptor reads service descriptor of OP_SERVICE_GET_DESCRIPTORS response
+func readServiceDescriptor(r io.Reader) (ServiceDescriptor, error) {
+	var d ServiceDescriptor
+	var err error
+
+	if d.Name, err = ReadOString(r); err != nil {
+		return d, errors.Wrapf(err, "failed to read name")
+	}
+	if d.ServiceClass, err = ReadOString(r); err != nil {
+		return d, errors.Wrapf(err, "failed to read service class")
+	}
+	if d.TotalCount, err = ReadInt(r); err != nil {
+		return d, errors.Wrapf(err, "failed to read total count")
+	}
+	if d.MaxPerNodeCount, err = ReadInt(r); err != nil {
+		return d, errors.Wrapf(err, "failed to read max per node count")
+	}
+	if d.CacheName, err = ReadOString(r); err != nil {
+		return d, errors.Wrapf(err, "failed to read cache name")
+	}
+	o, err := ReadObject(r)
+	if err != nil {
+		return d, errors.Wrapf(err, "failed to read origin node ID")
+	}
+	if id, ok := o.(uuid.UUID); ok {
+		d.OriginNodeID = id
+	}
+	if d.Platform, err = ReadByte(r); err != nil {
+		return d, errors.Wrapf(err, "failed to read platform")
+	}
+
+	return d, nil
+}
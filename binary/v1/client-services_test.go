@@ -0,0 +1,116 @@
+package ignite
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_writeServiceInvoke(t *testing.T) {
+	id := uuid.MustParse("d6589da7-f8b1-4687-b5bd-2ddc7362a4a4")
+
+	want := &bytes.Buffer{}
+	WriteOString(want, "svc")
+	WriteByte(want, serviceInvokeFlagKeepBinary)
+	WriteLong(want, 1500)
+	WriteInt(want, 1)
+	WriteUUID(want, id)
+	WriteOString(want, "add")
+	WriteInt(want, 2)
+	WriteOInt(want, 1)
+	WriteOString(want, "two")
+
+	w := &bytes.Buffer{}
+	if err := writeServiceInvoke(w, "svc", "add", []interface{}{int32(1), "two"}, true,
+		1500*time.Millisecond, []uuid.UUID{id}); err != nil {
+		t.Fatalf("writeServiceInvoke() error = %v", err)
+	}
+	if !reflect.DeepEqual(w.Bytes(), want.Bytes()) {
+		t.Errorf("writeServiceInvoke() = %#v, want %#v", w.Bytes(), want.Bytes())
+	}
+}
+
+func Test_writeServiceInvoke_timeout(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		want    int64
+	}{
+		{0, 0},
+		{time.Microsecond, 1},
+		{time.Millisecond, 1},
+		{1500 * time.Microsecond, 2},
+	}
+	for _, tt := range tests {
+		w := &bytes.Buffer{}
+		if err := writeServiceInvoke(w, "svc", "add", nil, false, tt.timeout, nil); err != nil {
+			t.Fatalf("writeServiceInvoke() error = %v", err)
+		}
+		r := bytes.NewReader(w.Bytes())
+		ReadOString(r)
+		ReadByte(r)
+		if got, _ := ReadLong(r); got != tt.want {
+			t.Errorf("writeServiceInvoke() timeout = %d, want %d for %v", got, tt.want, tt.timeout)
+		}
+	}
+}
+
+func Test_client_ServiceInvoke_context(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	c := newTestClient(t, ProtocolVersion170, newFeatures(FeatureServiceInvoke), func(code int16, payload []byte) (int32, []byte) {
+		<-block
+		return OperationStatusSuccess, nil
+	})
+	defer c.Close()
+
+	// deadline is passed already
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if _, err := c.ServiceInvoke(ctx, "svc", "add", nil, ServiceInvokeOptions{}); err != context.DeadlineExceeded {
+		t.Errorf("ServiceInvoke() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// server doesn't respond, request must be interrupted by context
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := c.ServiceInvoke(ctx, "svc", "add", nil, ServiceInvokeOptions{}); err != context.DeadlineExceeded {
+		t.Errorf("ServiceInvoke() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("ServiceInvoke() returned after %v", d)
+	}
+}
+
+func Test_readServiceDescriptor(t *testing.T) {
+	want := ServiceDescriptor{
+		Name:            "svc",
+		ServiceClass:    "org.apache.ignite.examples.SimpleService",
+		TotalCount:      1,
+		MaxPerNodeCount: 0,
+		CacheName:       "",
+		OriginNodeID:    uuid.MustParse("d6589da7-f8b1-4687-b5bd-2ddc7362a4a4"),
+		Platform:        ServicePlatformJava,
+	}
+
+	r := &bytes.Buffer{}
+	WriteOString(r, want.Name)
+	WriteOString(r, want.ServiceClass)
+	WriteInt(r, want.TotalCount)
+	WriteInt(r, want.MaxPerNodeCount)
+	WriteNull(r)
+	WriteOUUID(r, want.OriginNodeID)
+	WriteByte(r, want.Platform)
+
+	got, err := readServiceDescriptor(r)
+	if err != nil {
+		t.Fatalf("readServiceDescriptor() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readServiceDescriptor() = %#v, want %#v", got, want)
+	}
+}
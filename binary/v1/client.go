@@ -1,12 +1,17 @@
 package ignite
 
 import (
+	"context"
 	"crypto/tls"
-	"fmt"
 	"net"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/amsokol/ignite-go-client/binary/errors"
 	"github.com/amsokol/ignite-go-client/debug"
@@ -20,6 +25,37 @@ type ConnInfo struct {
 	Username, Password  string
 	Dialer              net.Dialer
 	TLSConfig           *tls.Config
+
+	// Heartbeat enables keep-alive loop which sends heartbeat requests to server
+	// when connection is idle. Failed heartbeat marks connection as broken.
+	Heartbeat bool
+
+	// HeartbeatInterval is interval of heartbeat requests.
+	// If server supports FeatureHeartbeat, interval is limited by a third of server idle timeout.
+	// Zero value means a third of server idle timeout or DefaultHeartbeatInterval
+	// if server idle timeout is unknown or disabled.
+	HeartbeatInterval time.Duration
+
+	// Dial is custom function to open connection, e.g. Replayer.Dial.
+	// If set, Dialer and TLSConfig are ignored.
+	Dial func(network, address string) (net.Conn, error)
+
+	// Recorder records request and response frames of the connection.
+	// Recording contains handshake credentials and cache data, so keep it safe.
+	Recorder *TrafficRecorder
+
+	// Interceptors are called for every request of the client (including handshake) in the order they are listed
+	Interceptors []Interceptor
+
+	// Metrics receives latency, throughput and error metrics of the client requests
+	Metrics MetricsSink
+
+	// Retry enables retries of the idempotent operations failed with transient errors
+	// and reopening of the broken connection
+	Retry *RetryPolicy
+
+	// CircuitBreaker enables failing fast when connection keeps failing
+	CircuitBreaker *CircuitBreaker
 }
 
 // Client is interface to communicate with Apache Ignite cluster.
@@ -31,6 +67,29 @@ type Client interface {
 	// Do sends request and receives response
 	Do(req Request, res Response) error
 
+	// ProtocolVersion returns protocol version negotiated with server
+	ProtocolVersion() ProtocolVersion
+
+	// FeatureSupported returns true if feature is supported by both client and server.
+	// Features are negotiated only for protocol v1.7.0+.
+	FeatureSupported(feature int) bool
+
+	// WithExpiryPolicy returns view of the client which applies expiry policy
+	// to the entries created, updated or accessed by Key-Value Queries.
+	// View shares connection with the client, so closing either of them closes both.
+	// Error is returned if policy is invalid (see ExpiryPolicy.Validate).
+	// Requires protocol v1.6.0+.
+	WithExpiryPolicy(p ExpiryPolicy) (Client, error)
+
+	// WithRetryPolicy returns view of the client which uses the retry policy instead of ConnInfo.Retry.
+	// View shares connection with the client, so closing either of them closes both.
+	WithRetryPolicy(p RetryPolicy) Client
+
+	// SetDeadline sets deadline of sending requests and receiving responses, zero value disables deadline.
+	// Deadline may be changed while request is in progress (e.g. to interrupt it when context is canceled).
+	// Request interrupted by deadline marks connection as broken.
+	SetDeadline(t time.Time) error
+
 	// Close closes connection.
 	// Returns:
 	// nil in case of success.
@@ -204,35 +263,303 @@ type Client interface {
 	// ResourceClose closes a resource, such as query cursor.
 	// https://apacheignite.readme.io/docs/binary-client-protocol-sql-operations#section-op_resource_close
 	ResourceClose(id int64) error
+
+	// Cluster Group
+	// See for details:
+	// https://ignite.apache.org/docs/latest/binary-client-protocol/binary-client-protocol#cluster-group
+
+	// ClusterGroupGetNodeIDs returns IDs of the cluster nodes matching the projection.
+	// Requires protocol v1.7.0+ and FeatureClusterGroups.
+	ClusterGroupGetNodeIDs(prj ClusterGroupProjection) ([]uuid.UUID, error)
+
+	// ClusterGroupGetNodeInfo returns information about the cluster nodes with given IDs.
+	// Requires protocol v1.7.0+ and FeatureClusterGroups.
+	ClusterGroupGetNodeInfo(ids []uuid.UUID) ([]ClusterNode, error)
+
+	// Services
+	// See for details:
+	// https://ignite.apache.org/docs/latest/binary-client-protocol/binary-client-protocol#services
+
+	// ServiceInvoke invokes method of the service deployed in the cluster.
+	// Arguments and result are marshaled the same way as cache keys and values.
+	// Context deadline is used as service call timeout if it is less than opts.Timeout.
+	// Requires protocol v1.7.0+ and FeatureServiceInvoke.
+	ServiceInvoke(ctx context.Context, name string, method string, args []interface{}, opts ServiceInvokeOptions) (interface{}, error)
+
+	// ServiceGetDescriptors returns descriptors of all services deployed in the cluster.
+	// Requires protocol v1.7.0+ and FeatureGetServiceDescriptors.
+	ServiceGetDescriptors() ([]ServiceDescriptor, error)
 }
 
-type client struct {
+// protocolVersionSetter is implemented by responses which format depends on protocol version
+type protocolVersionSetter interface {
+	setProtocolVersion(v ProtocolVersion)
+}
+
+// connection is connection state shared by the client and its views
+type connection struct {
 	debugID string
-	conn    net.Conn
 	mutex   *sync.Mutex
 
+	// protocol stores protocolState negotiated by handshake.
+	// It is replaced by reconnect with mutex locked, but it is read without mutex.
+	protocol atomic.Value
+
+	// conn stores socket with network connection, connection is nil if closed.
+	// Network connection is replaced with mutex locked, but it is read without mutex
+	// to set deadline or close connection while request is in progress.
+	conn atomic.Value
+
+	// broken stores error which made connection unusable
+	broken atomic.Value
+	// lastUsed is time of the last request in Unix nanoseconds
+	lastUsed int64
+	// deadline is deadline set by SetDeadline in Unix nanoseconds, zero if disabled
+	deadline int64
+	// done is closed when connection is closed
+	done      chan struct{}
+	closeOnce sync.Once
+	// heartbeatStopped is closed when heartbeat loop is exited, nil if heartbeats are disabled
+	heartbeatStopped chan struct{}
+	// invoker is chain of the interceptors, nil if there are no interceptors
+	invoker Invoker
+
+	// metrics is sink of the connection metrics, nil if metrics are disabled
+	metrics MetricsSink
+	// metricsOpen is 1 if connection is counted as open
+	metricsOpen int32
+	// cursors is count of the open cursors
+	cursors int64
+
+	// ci is connection parameters to reopen connection
+	ci ConnInfo
+	// interceptors is interceptors of the connection including metrics interceptor
+	interceptors []Interceptor
+	// retryPolicy is default retry policy, nil if retries are disabled
+	retryPolicy *RetryPolicy
+	// breaker is circuit breaker, nil if disabled
+	breaker *circuitBreaker
+	// closed is 1 if connection is closed by user
+	closed int32
+}
+
+// brokenError is wrapper to store error in atomic.Value
+type brokenError struct {
+	err error
+}
+
+// protocolState is protocol version and features negotiated by handshake
+type protocolState struct {
+	version  ProtocolVersion
+	features []byte
+}
+
+// socket is wrapper to store network connection in atomic.Value
+type socket struct {
+	conn net.Conn
+}
+
+// connectionHandle is owner of the connection, connection is closed by finalizer
+// if handle becomes unreachable. Heartbeat loop references connection but not the handle.
+type connectionHandle struct {
+	*connection
+}
+
+type client struct {
+	*connection
+
+	// handle is shared by the client and its views
+	handle *connectionHandle
+
+	// expiry policy to apply to key-value operations
+	expiryPolicy *ExpiryPolicy
+
+	// retry policy overriding retry policy of the connection
+	retryPolicy *RetryPolicy
+
+	// timeout of the requests exchange, zero if requests are limited by SetDeadline only
+	timeout time.Duration
+
+	// ctx interrupts requests exchange when it is done, nil if requests are not bound to context
+	ctx context.Context
+
 	Client
 }
 
 // IsConnected return true if connection to the cluster is active
 func (c *client) Connected() bool {
-	return c.conn != nil
+	return c.netConn() != nil && c.brokenErr() == nil
+}
+
+// state returns protocol version and features negotiated by handshake
+func (c *connection) state() protocolState {
+	s, _ := c.protocol.Load().(protocolState)
+	return s
+}
+
+// setState replaces protocol version and features
+func (c *connection) setState(version ProtocolVersion, features []byte) {
+	c.protocol.Store(protocolState{version: version, features: features})
+}
+
+// netConn returns network connection or nil if connection is closed
+func (c *connection) netConn() net.Conn {
+	if v, ok := c.conn.Load().(socket); ok {
+		return v.conn
+	}
+	return nil
+}
+
+// swapNetConn replaces network connection and returns the previous one
+func (c *connection) swapNetConn(conn net.Conn) net.Conn {
+	if v, ok := c.conn.Swap(socket{conn: conn}).(socket); ok {
+		return v.conn
+	}
+	return nil
+}
+
+// brokenErr returns error which made connection unusable or nil
+func (c *connection) brokenErr() error {
+	if v, ok := c.broken.Load().(brokenError); ok {
+		return v.err
+	}
+	return nil
+}
+
+// markBroken marks connection as unusable
+func (c *connection) markBroken(err error) {
+	if c.brokenErr() == nil {
+		c.broken.Store(brokenError{err: err})
+	}
 }
 
 // Do sends request and receives response
 func (c *client) Do(req Request, res Response) error {
+	if p := c.retry(); p != nil {
+		return c.doWithRetry(p, req, res)
+	}
+	return c.attempt(req, res, false)
+}
+
+// do sends request and receives response over the connection
+func (c *connection) do(info *OperationInfo, req Request, res Response) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	return c.exchange(info, req, res)
+}
+
+// exchange sends request and receives response, mutex must be locked by caller
+func (c *connection) exchange(info *OperationInfo, req Request, res Response) (err error) {
+	defer func() { info.Err = err }()
+
+	conn := c.netConn()
+	if conn == nil {
+		return &errors.ConnectionError{Err: errors.Errorf("connection is closed")}
+	}
+	if err := c.brokenErr(); err != nil {
+		return errors.Wrapf(err, "connection is broken")
+	}
+	start := time.Now()
+	atomic.StoreInt64(&c.lastUsed, start.UnixNano())
+	defer func() { info.Duration = time.Since(start) }()
+
+	var deadline time.Time
+	if info.timeout > 0 {
+		deadline = start.Add(info.timeout)
+	}
+	if info.ctx != nil {
+		if d, ok := info.ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+			deadline = d
+		}
+	}
+	if !deadline.IsZero() || (info.ctx != nil && info.ctx.Done() != nil) {
+		if !deadline.IsZero() {
+			if err = conn.SetDeadline(deadline); err != nil {
+				return &errors.ConnectionError{Err: errors.Wrapf(err, "failed to set request deadline")}
+			}
+		}
+		// restore deadline set by user
+		defer conn.SetDeadline(c.userDeadline())
+		if info.ctx != nil && info.ctx.Done() != nil {
+			defer interruptOnDone(info.ctx, conn)()
+		}
+	}
+
+	if r, ok := res.(protocolVersionSetter); ok {
+		r.setProtocolVersion(c.state().version)
+	}
+
 	// send request
-	if _, err := req.WriteTo(c.conn); err != nil {
-		return errors.Wrapf(err, "failed to send request to server")
+	if info.RequestSize, err = req.WriteTo(conn); err != nil {
+		// request may be sent partially
+		err = &errors.ConnectionError{Err: errors.Wrapf(err, "failed to send request to server")}
+		c.markBroken(err)
+		return err
 	}
 
 	// receive response
-	_, err := res.ReadFrom(c.conn)
+	if info.ResponseSize, err = res.ReadFrom(conn); err != nil {
+		// response may be read partially
+		if !errors.Is(err, errors.ErrDecode) {
+			err = &errors.ConnectionError{Err: err}
+		}
+		c.markBroken(err)
+		return err
+	}
 
-	return err
+	return nil
+}
+
+// SetDeadline sets deadline of sending requests and receiving responses, zero value disables deadline.
+func (c *client) SetDeadline(t time.Time) error {
+	conn := c.netConn()
+	if conn == nil {
+		return &errors.ConnectionError{Err: errors.Errorf("connection is closed")}
+	}
+	var d int64
+	if !t.IsZero() {
+		d = t.UnixNano()
+	}
+	atomic.StoreInt64(&c.deadline, d)
+	return conn.SetDeadline(t)
+}
+
+// interruptOnDone interrupts IO of the connection when context is done.
+// Returned function stops watching the context, it returns after IO can't be interrupted anymore.
+func interruptOnDone(ctx context.Context, conn net.Conn) func() {
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+	return func() {
+		close(stop)
+		<-stopped
+	}
+}
+
+// userDeadline returns deadline set by SetDeadline, zero value if deadline is disabled
+func (c *connection) userDeadline() time.Time {
+	if d := atomic.LoadInt64(&c.deadline); d != 0 {
+		return time.Unix(0, d)
+	}
+	return time.Time{}
+}
+
+// ProtocolVersion returns protocol version negotiated with server
+func (c *client) ProtocolVersion() ProtocolVersion {
+	return c.state().version
+}
+
+// FeatureSupported returns true if feature is supported by both client and server.
+func (c *client) FeatureSupported(feature int) bool {
+	return hasFeature(clientFeatures, feature) && hasFeature(c.state().features, feature)
 }
 
 // Close closes connection.
@@ -240,37 +567,88 @@ func (c *client) Do(req Request, res Response) error {
 // nil in case of success.
 // error object in case of error.
 func (c *client) Close() error {
-	if c.Connected() {
-		defer func() { c.conn = nil }()
-		return c.conn.Close()
+	return c.connection.close()
+}
+
+// close stops heartbeats and closes network connection
+func (c *connection) close() (err error) {
+	c.closeOnce.Do(func() {
+		atomic.StoreInt32(&c.closed, 1)
+		if c.done != nil {
+			close(c.done)
+		}
+	})
+	// socket is closed before waiting for heartbeat loop to interrupt heartbeat in progress,
+	// so loop is not blocked by server that doesn't respond
+	if conn := c.swapNetConn(nil); conn != nil {
+		err = conn.Close()
 	}
-	return nil
+	if c.heartbeatStopped != nil {
+		<-c.heartbeatStopped
+	}
+	c.metricsClosed()
+	return err
 }
 
-// Connect connects to the Apache Ignite cluster
-// Returns: client
-func Connect(ci ConnInfo) (Client, error) {
-	address := fmt.Sprintf("%s:%d", ci.Host, ci.Port)
+// isClosed returns true if connection is closed by user
+func (c *connection) isClosed() bool {
+	return atomic.LoadInt32(&c.closed) == 1
+}
+
+// dial opens network connection
+func dial(ci ConnInfo) (net.Conn, string, error) {
+	address := net.JoinHostPort(ci.Host, strconv.Itoa(ci.Port))
 
-	// connect
 	var conn net.Conn
 	var err error
-	if ci.TLSConfig != nil {
+	if ci.Dial != nil {
+		conn, err = ci.Dial(ci.Network, address)
+	} else if ci.TLSConfig != nil {
 		conn, err = tls.DialWithDialer(&ci.Dialer, ci.Network, address, ci.TLSConfig)
 	} else {
 		conn, err = ci.Dialer.Dial(ci.Network, address)
 	}
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to open connection")
+		return nil, address, &errors.ConnectionError{Err: errors.Wrapf(err, "failed to open connection")}
+	}
+	if ci.Recorder != nil {
+		conn = newRecordingConn(conn, ci.Recorder)
+	}
+	return conn, address, nil
+}
+
+// Connect connects to the Apache Ignite cluster
+// Returns: client
+func Connect(ci ConnInfo) (Client, error) {
+	// connect
+	conn, address, err := dial(ci)
+	if err != nil {
+		return nil, err
 	}
 
-	c := &client{conn: conn, debugID: strings.Join([]string{"network=", ci.Network, "', address='", address, "'"}, ""),
-		mutex: &sync.Mutex{}}
-	runtime.SetFinalizer(c, clientFinalizer)
+	c := &client{connection: &connection{
+		debugID: strings.Join([]string{"network=", ci.Network, "', address='", address, "'"}, ""),
+		mutex:   &sync.Mutex{}, ci: ci, retryPolicy: ci.Retry}}
+	version := ProtocolVersion{Major: ci.Major, Minor: ci.Minor, Patch: ci.Patch}
+	c.setState(version, nil)
+	c.swapNetConn(conn)
+	c.handle = &connectionHandle{connection: c.connection}
+	runtime.SetFinalizer(c.handle, connectionFinalizer)
+	if ci.CircuitBreaker != nil {
+		c.breaker = newCircuitBreaker(*ci.CircuitBreaker)
+	}
+	c.interceptors = ci.Interceptors
+	if ci.Metrics != nil {
+		c.metrics = ci.Metrics
+		c.interceptors = append(c.interceptors[:len(c.interceptors):len(c.interceptors)], metricsInterceptor)
+	}
+	if len(c.interceptors) > 0 {
+		c.invoker = chainInterceptors(c.interceptors, invokeDo)
+	}
 
 	// request and response
 	req := NewRequestHandshake(ci.Major, ci.Minor, ci.Patch, ci.Username, ci.Password)
-	res := &ResponseHandshake{}
+	res := NewResponseHandshake(ci.Major, ci.Minor, ci.Patch)
 
 	// make handshake
 	if err = c.Do(req, res); err != nil {
@@ -280,18 +658,27 @@ func Connect(ci ConnInfo) (Client, error) {
 
 	if !res.Success {
 		c.Close()
-		return nil, errors.Errorf("handshake failed: %s, server supported protocol version is v%d.%d.%d",
-			res.Message, res.Major, res.Minor, res.Patch)
+		return nil, &errors.HandshakeError{Status: res.Status, Message: res.Message,
+			Major: res.Major, Minor: res.Minor, Patch: res.Patch}
+	}
+	c.setState(version, res.Features)
+	c.metricsConnected()
+
+	if ci.Heartbeat {
+		if err = c.startHeartbeat(ci.HeartbeatInterval); err != nil {
+			c.Close()
+			return nil, errors.Wrapf(err, "failed to start heartbeats")
+		}
 	}
 
 	// return connected client
 	return c, nil
 }
 
-// clientFinalizer is resource leak spy
-func clientFinalizer(c *client) {
-	if c.Connected() {
-		debug.ResourceLeakLogger.Printf("client \"%s\" is not closed", c.debugID)
-		c.Close()
+// connectionFinalizer is resource leak spy
+func connectionFinalizer(h *connectionHandle) {
+	if h.netConn() != nil {
+		debug.ResourceLeakLogger.Printf("client \"%s\" is not closed", h.debugID)
+		h.close()
 	}
 }
@@ -1,8 +1,18 @@
 package ignite
 
 import (
+	"bytes"
 	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
 )
 
 func TestConnect(t *testing.T) {
@@ -66,3 +76,117 @@ func TestConnect(t *testing.T) {
 		})
 	}
 }
+
+// testHandler handles operation request of the test server.
+// Returns status and response body.
+type testHandler func(code int16, payload []byte) (int32, []byte)
+
+// newTestClient creates client connected to the test server over in-memory pipe.
+// Test server stops when client is closed.
+func newTestClient(t *testing.T, version ProtocolVersion, features []byte, h testHandler) *client {
+	cc, sc := net.Pipe()
+	go serveTestConn(sc, version, h)
+	c := &client{connection: &connection{debugID: t.Name(), mutex: &sync.Mutex{}}}
+	c.setState(version, features)
+	c.swapNetConn(cc)
+	return c
+}
+
+// serveTestConn handles operation requests until connection is closed
+func serveTestConn(sc net.Conn, version ProtocolVersion, h testHandler) {
+	defer sc.Close()
+	for {
+		var l int32
+		if err := binary.Read(sc, binary.LittleEndian, &l); err != nil {
+			return
+		}
+		b := make([]byte, l)
+		if _, err := io.ReadFull(sc, b); err != nil {
+			return
+		}
+		r := bytes.NewReader(b)
+		code, _ := ReadShort(r)
+		uid, _ := ReadLong(r)
+		status, body := h(code, b[10:])
+
+		res := &bytes.Buffer{}
+		WriteLong(res, uid)
+		if version.AtLeast(ProtocolVersion140) {
+			if status != OperationStatusSuccess {
+				WriteShort(res, ResponseFlagError)
+				WriteInt(res, status)
+				WriteOString(res, "test error")
+			} else {
+				WriteShort(res, 0)
+			}
+		} else {
+			WriteInt(res, status)
+			if status != OperationStatusSuccess {
+				WriteOString(res, "test error")
+			}
+		}
+		res.Write(body)
+		if err := WriteInt(sc, int32(res.Len())); err != nil {
+			return
+		}
+		if _, err := res.WriteTo(sc); err != nil {
+			return
+		}
+	}
+}
+
+// newTestDial returns ConnInfo.Dial function which connects to the test server.
+// Test server accepts handshake with features and handles operation requests.
+func newTestDial(features []byte, h testHandler) func(network, address string) (net.Conn, error) {
+	return func(network, address string) (net.Conn, error) {
+		cc, sc := net.Pipe()
+		go func() {
+			b, err := readFrame(sc)
+			if err != nil || len(b) < 7 {
+				sc.Close()
+				return
+			}
+			r := bytes.NewReader(b[1:])
+			major, _ := ReadShort(r)
+			minor, _ := ReadShort(r)
+			patch, _ := ReadShort(r)
+			version := ProtocolVersion{Major: int(major), Minor: int(minor), Patch: int(patch)}
+
+			res := &bytes.Buffer{}
+			WriteBool(res, true)
+			if version.AtLeast(ProtocolVersion170) {
+				WriteOArrayBytes(res, features)
+			}
+			if version.AtLeast(ProtocolVersion140) {
+				WriteOUUID(res, uuid.UUID{})
+			}
+			WriteInt(sc, int32(res.Len()))
+			if _, err = res.WriteTo(sc); err != nil {
+				sc.Close()
+				return
+			}
+			serveTestConn(sc, version, h)
+		}()
+		return cc, nil
+	}
+}
+
+func Test_client_SetDeadline(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	c := newTestClient(t, ProtocolVersion110, nil, func(code int16, payload []byte) (int32, []byte) {
+		<-block
+		return OperationStatusSuccess, nil
+	})
+	defer c.Close()
+
+	if err := c.SetDeadline(time.Now().Add(20 * time.Millisecond)); err != nil {
+		t.Fatalf("SetDeadline() error = %v", err)
+	}
+	if _, err := c.CacheGetNames(); !errors.Is(err, ErrConnectionLost) {
+		t.Errorf("CacheGetNames() error = %v, want ErrConnectionLost", err)
+	}
+	if c.Connected() {
+		t.Errorf("Connected() = true after request is interrupted by deadline")
+	}
+}
@@ -0,0 +1,325 @@
+package ignite
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+const (
+	// DefaultDataStreamerBufferSize is default count of entries sent to server by one request
+	DefaultDataStreamerBufferSize = 1024
+	// DefaultDataStreamerConnections is default count of connections used to send entries in parallel
+	DefaultDataStreamerConnections = 4
+)
+
+// DataStreamerOptions contains options of DataStreamer
+type DataStreamerOptions struct {
+	// Binary flag of the operations
+	Binary bool
+
+	// BufferSize is count of entries sent to server by one request.
+	// Zero value means DefaultDataStreamerBufferSize.
+	BufferSize int
+
+	// FlushInterval is interval to flush buffered entries automatically.
+	// Zero value disables automatic flushing, so entries are sent
+	// when buffer is full or Flush (Close) is called.
+	FlushInterval time.Duration
+
+	// Connections is count of connections used to send entries in parallel.
+	// Zero value means DefaultDataStreamerConnections.
+	Connections int
+
+	// MaxPendingBuffers is count of full buffers waiting to be sent.
+	// Add blocks when limit is reached until one of the buffers is sent.
+	// Zero value means twice the count of connections.
+	MaxPendingBuffers int
+
+	// AllowOverwrite allows to overwrite existing entries with OP_CACHE_PUT_ALL.
+	// If false entries are put one by one with OP_CACHE_PUT_IF_ABSENT, that is much slower.
+	AllowOverwrite bool
+}
+
+// DataStreamer loads large amount of entries into cache.
+// DataStreamer is thread safe.
+type DataStreamer interface {
+	// Add adds entry to the buffer. Buffer is sent to server when it is full.
+	// Add blocks if there are too many buffers waiting to be sent.
+	Add(key interface{}, value interface{}) error
+
+	// Flush sends buffered entries to server and waits until all the sent entries are stored.
+	// Returns *DataStreamerError if some buffers were failed since previous Flush.
+	Flush() error
+
+	// Close flushes buffered entries and closes connections.
+	// Returns *DataStreamerError if some buffers were failed since previous Flush.
+	Close() error
+}
+
+// DataStreamerBufferError describes failed buffer
+type DataStreamerBufferError struct {
+	// Entries of the failed buffer
+	Entries map[interface{}]interface{}
+
+	// Error of the operation
+	Err error
+}
+
+// DataStreamerError contains errors of the failed buffers
+type DataStreamerError struct {
+	Buffers []DataStreamerBufferError
+}
+
+// Error returns error message
+func (e *DataStreamerError) Error() string {
+	var entries int
+	msgs := make([]string, 0, len(e.Buffers))
+	for _, b := range e.Buffers {
+		entries += len(b.Entries)
+		msgs = append(msgs, b.Err.Error())
+	}
+	return fmt.Sprintf("failed to stream %d entries in %d buffers: %s", entries, len(e.Buffers), strings.Join(msgs, "; "))
+}
+
+type dataStreamer struct {
+	cache   string
+	opts    DataStreamerOptions
+	clients []Client
+
+	// buffer is entries to be sent
+	mutex  sync.Mutex
+	buffer map[interface{}]interface{}
+	closed bool
+
+	// queue is buffers waiting to be sent
+	queue   chan map[interface{}]interface{}
+	workers sync.WaitGroup
+
+	// pending is count of the buffers taken from buffer and not stored yet
+	pendingMutex sync.Mutex
+	pendingCond  *sync.Cond
+	pending      int
+
+	// failed contains errors since previous Flush
+	failedMutex sync.Mutex
+	failed      []DataStreamerBufferError
+
+	done chan struct{}
+}
+
+// NewDataStreamer opens connections to the cluster and creates data streamer for the cache.
+func NewDataStreamer(ci ConnInfo, cache string, opts DataStreamerOptions) (DataStreamer, error) {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = DefaultDataStreamerBufferSize
+	}
+	if opts.Connections <= 0 {
+		opts.Connections = DefaultDataStreamerConnections
+	}
+	if opts.MaxPendingBuffers <= 0 {
+		opts.MaxPendingBuffers = 2 * opts.Connections
+	}
+
+	clients := make([]Client, 0, opts.Connections)
+	for i := 0; i < opts.Connections; i++ {
+		c, err := Connect(ci)
+		if err != nil {
+			for _, c := range clients {
+				c.Close()
+			}
+			return nil, errors.Wrapf(err, "failed to open connection with index %d", i)
+		}
+		clients = append(clients, c)
+	}
+
+	return newDataStreamer(clients, cache, opts), nil
+}
+
+// newDataStreamer creates data streamer over opened connections
+func newDataStreamer(clients []Client, cache string, opts DataStreamerOptions) *dataStreamer {
+	s := &dataStreamer{
+		cache:   cache,
+		opts:    opts,
+		clients: clients,
+		buffer:  make(map[interface{}]interface{}, opts.BufferSize),
+		queue:   make(chan map[interface{}]interface{}, opts.MaxPendingBuffers),
+		done:    make(chan struct{}),
+	}
+	s.pendingCond = sync.NewCond(&s.pendingMutex)
+	for _, c := range clients {
+		s.workers.Add(1)
+		go s.worker(c)
+	}
+	if opts.FlushInterval > 0 {
+		go s.flusher(opts.FlushInterval)
+	}
+	return s
+}
+
+// Add adds entry to the buffer.
+func (s *dataStreamer) Add(key interface{}, value interface{}) error {
+	if key == nil {
+		return errors.Errorf("key must not be nil")
+	}
+	if t := reflect.TypeOf(key); !t.Comparable() {
+		return errors.Errorf("unsupported key type for data streamer: %s", t)
+	}
+
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return errors.Errorf("data streamer is closed")
+	}
+	s.buffer[key] = value
+	var b map[interface{}]interface{}
+	if len(s.buffer) >= s.opts.BufferSize {
+		b = s.take()
+	}
+	s.mutex.Unlock()
+
+	s.enqueue(b)
+	return nil
+}
+
+// Flush sends buffered entries to server and waits until all the sent entries are stored.
+func (s *dataStreamer) Flush() error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return errors.Errorf("data streamer is closed")
+	}
+	b := s.take()
+	s.mutex.Unlock()
+
+	s.enqueue(b)
+	s.waitPending()
+	return s.takeErrors()
+}
+
+// Close flushes buffered entries and closes connections.
+func (s *dataStreamer) Close() error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil
+	}
+	b := s.take()
+	s.closed = true
+	close(s.done)
+	s.mutex.Unlock()
+
+	// buffers are not taken after streamer is closed,
+	// so queue is closed when the taken buffers are stored
+	s.enqueue(b)
+	s.waitPending()
+	close(s.queue)
+	s.workers.Wait()
+	err := s.takeErrors()
+	for _, c := range s.clients {
+		c.Close()
+	}
+	return err
+}
+
+// take returns buffered entries and replaces buffer with empty one, nil if buffer is empty.
+// Must be called under lock.
+func (s *dataStreamer) take() map[interface{}]interface{} {
+	if len(s.buffer) == 0 {
+		return nil
+	}
+	b := s.buffer
+	s.buffer = make(map[interface{}]interface{}, s.opts.BufferSize)
+
+	s.pendingMutex.Lock()
+	s.pending++
+	s.pendingMutex.Unlock()
+	return b
+}
+
+// enqueue sends taken buffer to the queue.
+// Must be called without lock, it blocks if there are too many pending buffers (backpressure).
+func (s *dataStreamer) enqueue(b map[interface{}]interface{}) {
+	if b != nil {
+		s.queue <- b
+	}
+}
+
+// stored marks taken buffer as stored (or failed)
+func (s *dataStreamer) stored() {
+	s.pendingMutex.Lock()
+	defer s.pendingMutex.Unlock()
+	if s.pending--; s.pending == 0 {
+		s.pendingCond.Broadcast()
+	}
+}
+
+// waitPending waits until all the taken buffers are stored
+func (s *dataStreamer) waitPending() {
+	s.pendingMutex.Lock()
+	defer s.pendingMutex.Unlock()
+	for s.pending > 0 {
+		s.pendingCond.Wait()
+	}
+}
+
+// worker sends buffers from the queue using the client
+func (s *dataStreamer) worker(c Client) {
+	defer s.workers.Done()
+	for b := range s.queue {
+		if err := s.send(c, b); err != nil {
+			s.failedMutex.Lock()
+			s.failed = append(s.failed, DataStreamerBufferError{Entries: b, Err: err})
+			s.failedMutex.Unlock()
+		}
+		s.stored()
+	}
+}
+
+// send stores entries in the cache
+func (s *dataStreamer) send(c Client, b map[interface{}]interface{}) error {
+	if s.opts.AllowOverwrite {
+		return c.CachePutAll(s.cache, s.opts.Binary, b)
+	}
+	for k, v := range b {
+		if _, err := c.CachePutIfAbsent(s.cache, s.opts.Binary, k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flusher sends buffered entries periodically
+func (s *dataStreamer) flusher(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-t.C:
+			var b map[interface{}]interface{}
+			s.mutex.Lock()
+			if !s.closed {
+				b = s.take()
+			}
+			s.mutex.Unlock()
+			s.enqueue(b)
+		}
+	}
+}
+
+// takeErrors returns errors since previous call
+func (s *dataStreamer) takeErrors() error {
+	s.failedMutex.Lock()
+	defer s.failedMutex.Unlock()
+	if len(s.failed) == 0 {
+		return nil
+	}
+	err := &DataStreamerError{Buffers: s.failed}
+	s.failed = nil
+	return err
+}
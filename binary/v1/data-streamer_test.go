@@ -0,0 +1,167 @@
+package ignite
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_dataStreamer(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           DataStreamerOptions
+		entries        int
+		failPutAll     bool
+		wantPutAll     int32
+		wantPutIfAbsnt int32
+		wantErr        bool
+	}{
+		{
+			name:       "1",
+			opts:       DataStreamerOptions{BufferSize: 10, Connections: 2, AllowOverwrite: true},
+			entries:    95,
+			wantPutAll: 10,
+		},
+		{
+			name:           "2",
+			opts:           DataStreamerOptions{BufferSize: 10, Connections: 2},
+			entries:        15,
+			wantPutIfAbsnt: 15,
+		},
+		{
+			name:       "3",
+			opts:       DataStreamerOptions{BufferSize: 10, Connections: 3, MaxPendingBuffers: 1, AllowOverwrite: true},
+			entries:    30,
+			failPutAll: true,
+			wantPutAll: 3,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var putAll, putIfAbsent, stored int32
+			h := func(code int16, payload []byte) (int32, []byte) {
+				switch code {
+				case OpCachePutAll:
+					atomic.AddInt32(&putAll, 1)
+					if tt.failPutAll {
+						return 1, nil
+					}
+					// cache ID, flags, count
+					count, _ := ReadInt(bytes.NewReader(payload[5:]))
+					atomic.AddInt32(&stored, count)
+					return OperationStatusSuccess, nil
+				case OpCachePutIfAbsent:
+					atomic.AddInt32(&putIfAbsent, 1)
+					atomic.AddInt32(&stored, 1)
+					w := newRequest()
+					WriteBool(&w, true)
+					return OperationStatusSuccess, w.payload.Bytes()
+				default:
+					return 2, nil
+				}
+			}
+			clients := make([]Client, 0, tt.opts.Connections)
+			for i := 0; i < tt.opts.Connections; i++ {
+				clients = append(clients, newTestClient(t, ProtocolVersion110, nil, h))
+			}
+			if tt.opts.MaxPendingBuffers == 0 {
+				tt.opts.MaxPendingBuffers = 2 * tt.opts.Connections
+			}
+			s := newDataStreamer(clients, "TestCache", tt.opts)
+
+			for i := 0; i < tt.entries; i++ {
+				if err := s.Add(int32(i), "value"); err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+			}
+			err := s.Close()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Close() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if e, ok := err.(*DataStreamerError); !ok || len(e.Buffers) != int(tt.wantPutAll) {
+					t.Errorf("Close() error = %#v, want *DataStreamerError with %d buffers", err, tt.wantPutAll)
+				}
+			} else if stored != int32(tt.entries) {
+				t.Errorf("stored entries = %d, want %d", stored, tt.entries)
+			}
+			if putAll != tt.wantPutAll {
+				t.Errorf("OP_CACHE_PUT_ALL count = %d, want %d", putAll, tt.wantPutAll)
+			}
+			if putIfAbsent != tt.wantPutIfAbsnt {
+				t.Errorf("OP_CACHE_PUT_IF_ABSENT count = %d, want %d", putIfAbsent, tt.wantPutIfAbsnt)
+			}
+			if err := s.Add(int32(0), "value"); err == nil {
+				t.Errorf("Add() error = nil after Close()")
+			}
+		})
+	}
+}
+
+func Test_dataStreamer_concurrentFlush(t *testing.T) {
+	var stored int32
+	c := newTestClient(t, ProtocolVersion110, nil, func(code int16, payload []byte) (int32, []byte) {
+		count, _ := ReadInt(bytes.NewReader(payload[5:]))
+		atomic.AddInt32(&stored, count)
+		return OperationStatusSuccess, nil
+	})
+	s := newDataStreamer([]Client{c}, "TestCache", DataStreamerOptions{
+		BufferSize: 3, MaxPendingBuffers: 1, AllowOverwrite: true})
+
+	// Flush is called while buffers are added by other goroutines
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if err := s.Add(int32(g*1000+i), "value"); err != nil {
+					t.Errorf("Add() error = %v", err)
+					return
+				}
+				if i%10 == 0 {
+					if err := s.Flush(); err != nil {
+						t.Errorf("Flush() error = %v", err)
+						return
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if n := atomic.LoadInt32(&stored); n != 200 {
+		t.Errorf("stored entries = %d, want 200", n)
+	}
+}
+
+func Test_dataStreamer_FlushInterval(t *testing.T) {
+	var stored int32
+	c := newTestClient(t, ProtocolVersion110, nil, func(code int16, payload []byte) (int32, []byte) {
+		count, _ := ReadInt(bytes.NewReader(payload[5:]))
+		atomic.AddInt32(&stored, count)
+		return OperationStatusSuccess, nil
+	})
+	s := newDataStreamer([]Client{c}, "TestCache", DataStreamerOptions{
+		BufferSize: 100, FlushInterval: 20 * time.Millisecond, MaxPendingBuffers: 1, AllowOverwrite: true})
+	defer s.Close()
+
+	if err := s.Add("key", []byte{1, 2, 3}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&stored) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&stored); n != 1 {
+		t.Errorf("stored entries = %d, want 1", n)
+	}
+	if err := s.Add([]byte{1}, "value"); err == nil {
+		t.Errorf("Add() error = nil for unsupported key type")
+	}
+}
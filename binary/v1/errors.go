@@ -0,0 +1,34 @@
+package ignite
+
+import (
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+// Errors returned by the client, use errors.Is to check them:
+//
+//	if errors.Is(err, ignite.ErrCacheNotFound) {
+//		...
+//	}
+var (
+	// ErrCacheNotFound is returned if cache does not exist (status 1000)
+	ErrCacheNotFound = errors.ErrCacheNotFound
+	// ErrCacheExists is returned if cache already exists (status 1001)
+	ErrCacheExists = errors.ErrCacheExists
+	// ErrTooManyCursors is returned if server limit of the open cursors is reached (status 1010)
+	ErrTooManyCursors = errors.ErrTooManyCursors
+	// ErrResourceNotFound is returned if resource (e.g. cursor) does not exist (status 1011)
+	ErrResourceNotFound = errors.ErrResourceNotFound
+	// ErrSecurityViolation is returned if operation is not authorized (status 1012)
+	ErrSecurityViolation = errors.ErrSecurityViolation
+	// ErrAuthFailed is returned if server rejected handshake because of invalid credentials (status 2000)
+	ErrAuthFailed = errors.ErrAuthFailed
+
+	// ErrConnectionLost is returned if connection can't be opened or is lost (see errors.ConnectionError)
+	ErrConnectionLost = errors.ErrConnectionLost
+	// ErrHandshakeFailed is returned if server rejected handshake (see errors.HandshakeError)
+	ErrHandshakeFailed = errors.ErrHandshakeFailed
+	// ErrDecode is returned if server response can't be decoded (see errors.DecodeError)
+	ErrDecode = errors.ErrDecode
+	// ErrCircuitOpen is returned if request is rejected by open circuit breaker (see ConnInfo.CircuitBreaker)
+	ErrCircuitOpen = errors.ErrCircuitOpen
+)
@@ -0,0 +1,125 @@
+package ignite
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+func TestErrors(t *testing.T) {
+	h := func(code int16, payload []byte) (int32, []byte) {
+		switch code {
+		case OpCacheCreateWithName:
+			return errors.StatusCacheExists, nil
+		case OpQuerySQLFieldsCursorGetPage:
+			return errors.StatusResourceDoesNotExist, nil
+		default:
+			return errors.StatusCacheDoesNotExist, nil
+		}
+	}
+	c, err := Connect(ConnInfo{Network: "tcp", Host: "127.0.0.1", Port: 10800, Major: 1, Minor: 1, Patch: 0,
+		Dial: newTestDial(nil, h)})
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer c.Close()
+
+	_, err = c.CacheGet("TestCache", false, "key")
+	if !errors.Is(err, ErrCacheNotFound) || errors.Is(err, ErrCacheExists) || errors.Is(err, ErrConnectionLost) {
+		t.Errorf("CacheGet() error = %v, want ErrCacheNotFound", err)
+	}
+	var ie *errors.IgniteError
+	if !errors.As(err, &ie) || ie.IgniteStatus != errors.StatusCacheDoesNotExist {
+		t.Errorf("CacheGet() error = %v, want *IgniteError with status 1000", err)
+	}
+	if err = c.CacheCreateWithName("TestCache"); !errors.Is(err, ErrCacheExists) {
+		t.Errorf("CacheCreateWithName() error = %v, want ErrCacheExists", err)
+	}
+	if _, err = c.QuerySQLFieldsCursorGetPage(1, 1); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("QuerySQLFieldsCursorGetPage() error = %v, want ErrResourceNotFound", err)
+	}
+}
+
+func TestErrors_Connection(t *testing.T) {
+	// server closes connection after handshake
+	c, err := Connect(ConnInfo{Network: "tcp", Host: "127.0.0.1", Port: 10800, Major: 1, Minor: 1, Patch: 0,
+		Dial: newTestServerDial(func(sc net.Conn) {
+			res := &bytes.Buffer{}
+			WriteBool(res, true)
+			WriteInt(sc, int32(res.Len()))
+			sc.Write(res.Bytes())
+		})})
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer c.Close()
+	_, err = c.CacheGet("TestCache", false, "key")
+	if !errors.Is(err, ErrConnectionLost) || errors.Is(err, ErrDecode) {
+		t.Errorf("CacheGet() error = %v, want ErrConnectionLost", err)
+	}
+	// connection is broken
+	if _, err = c.CacheGet("TestCache", false, "key"); !errors.Is(err, ErrConnectionLost) {
+		t.Errorf("CacheGet() error = %v, want ErrConnectionLost", err)
+	}
+
+	_, err = Connect(ConnInfo{Network: "tcp", Host: "127.0.0.1", Port: 10800, Major: 1, Minor: 1, Patch: 0,
+		Dial: func(network, address string) (net.Conn, error) {
+			return nil, errors.Errorf("connection refused")
+		}})
+	if !errors.Is(err, ErrConnectionLost) {
+		t.Errorf("Connect() error = %v, want ErrConnectionLost", err)
+	}
+}
+
+// newTestServerDial returns dial function of the server which reads handshake request,
+// calls handshake function to answer it and closes connection
+func newTestServerDial(handshake func(sc net.Conn)) func(network, address string) (net.Conn, error) {
+	return func(network, address string) (net.Conn, error) {
+		cc, sc := net.Pipe()
+		go func() {
+			defer sc.Close()
+			if _, err := readFrame(sc); err != nil {
+				return
+			}
+			handshake(sc)
+		}()
+		return cc, nil
+	}
+}
+
+func TestErrors_Handshake(t *testing.T) {
+	res := &bytes.Buffer{}
+	WriteBool(res, false)
+	WriteShort(res, 1)
+	WriteShort(res, 1)
+	WriteShort(res, 0)
+	WriteOString(res, "authentication failed")
+	WriteInt(res, errors.StatusAuthFailed)
+
+	_, err := Connect(ConnInfo{Network: "tcp", Host: "127.0.0.1", Port: 10800, Major: 1, Minor: 1, Patch: 0,
+		Dial: newTestServerDial(func(sc net.Conn) {
+			WriteInt(sc, int32(res.Len()))
+			sc.Write(res.Bytes())
+		})})
+	if !errors.Is(err, ErrHandshakeFailed) || !errors.Is(err, ErrAuthFailed) || errors.Is(err, ErrConnectionLost) {
+		t.Errorf("Connect() error = %v, want ErrHandshakeFailed and ErrAuthFailed", err)
+	}
+	var he *errors.HandshakeError
+	if !errors.As(err, &he) || he.Major != 1 || he.Minor != 1 || he.Message != "authentication failed" {
+		t.Errorf("Connect() error = %v, want *HandshakeError", err)
+	}
+}
+
+func TestErrors_Decode(t *testing.T) {
+	if _, err := ReadObject(bytes.NewReader([]byte{127})); !errors.Is(err, ErrDecode) {
+		t.Errorf("ReadObject() error = %v, want ErrDecode", err)
+	}
+	r := NewResponseOperation(1)
+	// response with request ID 2
+	b := []byte{12, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	if _, err := r.ReadFrom(bytes.NewReader(b)); !errors.Is(err, ErrDecode) || errors.Is(err, ErrConnectionLost) {
+		t.Errorf("ResponseOperation.ReadFrom() error = %v, want ErrDecode", err)
+	}
+}
@@ -0,0 +1,131 @@
+package ignite
+
+import (
+	"io"
+	"time"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+const (
+	// ExpiryUnchanged leaves expiration time of the entry unchanged
+	ExpiryUnchanged time.Duration = 0
+	// ExpiryEternal means the entry never expires
+	ExpiryEternal time.Duration = -1
+	// ExpiryImmediately means the entry expires immediately
+	ExpiryImmediately time.Duration = -2
+)
+
+const (
+	expiryMillisUnchanged = -2
+	expiryMillisEternal   = -1
+	expiryMillisZero      = 0
+)
+
+// ExpiryPolicy defines time to live of cache entries
+type ExpiryPolicy struct {
+	// Create is time to live of the entry after it is created.
+	Create time.Duration
+
+	// Update is time to live of the entry after it is updated.
+	Update time.Duration
+
+	// Access is time to live of the entry after it is accessed.
+	Access time.Duration
+}
+
+// Validate returns error if any of durations is negative and is not ExpiryEternal or ExpiryImmediately
+// (e.g. it is result of subtraction bug)
+func (p ExpiryPolicy) Validate() error {
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"create", p.Create},
+		{"update", p.Update},
+		{"access", p.Access},
+	} {
+		if !validExpiry(d.value) {
+			return errors.Errorf("invalid expiry policy: %s duration must not be negative, got %v", d.name, d.value)
+		}
+	}
+	return nil
+}
+
+// validExpiry returns true if duration is not negative or it is special value
+func validExpiry(d time.Duration) bool {
+	return d >= 0 || d == ExpiryEternal || d == ExpiryImmediately
+}
+
+// WithExpiryPolicy returns view of the client which applies expiry policy to Key-Value Queries.
+func (c *client) WithExpiryPolicy(p ExpiryPolicy) (Client, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &client{connection: c.connection, handle: c.handle, expiryPolicy: &p, retryPolicy: c.retryPolicy}, nil
+}
+
+// writeExpiryPolicy writes expiry policy durations in milliseconds, invalid policy is rejected
+func writeExpiryPolicy(w io.Writer, p ExpiryPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if err := WriteLong(w, expiryToMillis(p.Create)); err != nil {
+		return errors.Wrapf(err, "failed to write expiry policy create duration")
+	}
+	if err := WriteLong(w, expiryToMillis(p.Update)); err != nil {
+		return errors.Wrapf(err, "failed to write expiry policy update duration")
+	}
+	if err := WriteLong(w, expiryToMillis(p.Access)); err != nil {
+		return errors.Wrapf(err, "failed to write expiry policy access duration")
+	}
+	return nil
+}
+
+// readExpiryPolicy reads expiry policy durations in milliseconds
+func readExpiryPolicy(r io.Reader) (ExpiryPolicy, error) {
+	var p ExpiryPolicy
+	var ms [3]int64
+	for i := range ms {
+		v, err := ReadLong(r)
+		if err != nil {
+			return p, errors.Wrapf(err, "failed to read expiry policy duration with index %d", i)
+		}
+		ms[i] = v
+	}
+	p.Create = expiryFromMillis(ms[0])
+	p.Update = expiryFromMillis(ms[1])
+	p.Access = expiryFromMillis(ms[2])
+	return p, nil
+}
+
+// expiryToMillis converts duration to protocol format
+func expiryToMillis(d time.Duration) int64 {
+	switch {
+	case d == ExpiryUnchanged:
+		return expiryMillisUnchanged
+	case d == ExpiryEternal:
+		return expiryMillisEternal
+	case d == ExpiryImmediately:
+		return expiryMillisZero
+	case d < time.Millisecond:
+		// the least duration supported by server
+		return 1
+	default:
+		return int64(d / time.Millisecond)
+	}
+}
+
+// expiryFromMillis converts duration from protocol format
+func expiryFromMillis(ms int64) time.Duration {
+	switch ms {
+	case expiryMillisUnchanged:
+		return ExpiryUnchanged
+	case expiryMillisEternal:
+		return ExpiryEternal
+	case expiryMillisZero:
+		return ExpiryImmediately
+	default:
+		return time.Duration(ms) * time.Millisecond
+	}
+}
@@ -0,0 +1,105 @@
+package ignite
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_expiryToMillis(t *testing.T) {
+	tests := []struct {
+		name string
+		d    time.Duration
+		want int64
+	}{
+		{name: "unchanged", d: ExpiryUnchanged, want: -2},
+		{name: "eternal", d: ExpiryEternal, want: -1},
+		{name: "immediately", d: ExpiryImmediately, want: 0},
+		{name: "less than millisecond", d: time.Microsecond, want: 1},
+		{name: "minute", d: time.Minute, want: 60000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := expiryToMillis(tt.d)
+			if got != tt.want {
+				t.Errorf("expiryToMillis() = %v, want %v", got, tt.want)
+			}
+			if tt.d >= time.Millisecond || tt.d <= 0 {
+				if d := expiryFromMillis(got); d != tt.d {
+					t.Errorf("expiryFromMillis() = %v, want %v", d, tt.d)
+				}
+			}
+		})
+	}
+}
+
+func Test_client_writeCacheFlags(t *testing.T) {
+	c := &client{connection: &connection{}}
+	c.setState(ProtocolVersion160, nil)
+
+	w := &bytes.Buffer{}
+	if err := c.writeCacheFlags(w, true); err != nil {
+		t.Fatalf("writeCacheFlags() error = %v", err)
+	}
+	if want := []byte{1}; !reflect.DeepEqual(w.Bytes(), want) {
+		t.Errorf("writeCacheFlags() = %#v, want %#v", w.Bytes(), want)
+	}
+
+	view, err := c.WithExpiryPolicy(ExpiryPolicy{Create: time.Second, Access: ExpiryEternal})
+	if err != nil {
+		t.Fatalf("WithExpiryPolicy() error = %v", err)
+	}
+	v := view.(*client)
+	w.Reset()
+	if err := v.writeCacheFlags(w, false); err != nil {
+		t.Fatalf("writeCacheFlags() error = %v", err)
+	}
+	want := []byte{4,
+		0xe8, 0x03, 0, 0, 0, 0, 0, 0,
+		0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	if !reflect.DeepEqual(w.Bytes(), want) {
+		t.Errorf("writeCacheFlags() = %#v, want %#v", w.Bytes(), want)
+	}
+	if v.connection != c.connection {
+		t.Errorf("WithExpiryPolicy() view must share connection with the client")
+	}
+
+	old := &client{connection: &connection{}}
+	old.setState(ProtocolVersion110, nil)
+	view, err = old.WithExpiryPolicy(ExpiryPolicy{})
+	if err != nil {
+		t.Fatalf("WithExpiryPolicy() error = %v", err)
+	}
+	if err := view.(*client).writeCacheFlags(w, false); err == nil {
+		t.Errorf("writeCacheFlags() error = nil, want error for protocol %s", old.ProtocolVersion())
+	}
+
+	if _, err := c.WithExpiryPolicy(ExpiryPolicy{Update: -5 * time.Second}); err == nil {
+		t.Errorf("WithExpiryPolicy() error = nil, want error for negative duration")
+	}
+}
+
+func TestExpiryPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       ExpiryPolicy
+		wantErr bool
+	}{
+		{"special values", ExpiryPolicy{Create: ExpiryEternal, Update: ExpiryImmediately, Access: ExpiryUnchanged}, false},
+		{"durations", ExpiryPolicy{Create: time.Minute, Update: time.Microsecond}, false},
+		{"negative create", ExpiryPolicy{Create: -5 * time.Second}, true},
+		{"negative access", ExpiryPolicy{Access: -3}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := writeExpiryPolicy(&bytes.Buffer{}, tt.p); (err != nil) != tt.wantErr {
+				t.Errorf("writeExpiryPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package ignite
+
+import (
+	"bytes"
+	"strconv"
+	"sync/atomic"
+)
+
+// Names of the client metrics
+const (
+	// MetricOperations is counter of the operations by "op" and "status" labels.
+	// Status is status code returned by server or "error" if request failed before response is received.
+	MetricOperations = "ignite_client_operations_total"
+	// MetricOperationDuration is histogram of the operation latency in seconds by "op" label
+	MetricOperationDuration = "ignite_client_operation_duration_seconds"
+	// MetricSentBytes is counter of the bytes sent to server by "op" label
+	MetricSentBytes = "ignite_client_sent_bytes_total"
+	// MetricReceivedBytes is counter of the bytes received from server by "op" label
+	MetricReceivedBytes = "ignite_client_received_bytes_total"
+	// MetricHandshakes is counter of the handshakes by "result" label ("success", "rejected" or "error")
+	MetricHandshakes = "ignite_client_handshakes_total"
+	// MetricOpenConnections is gauge of the connected clients
+	MetricOpenConnections = "ignite_client_open_connections"
+	// MetricInFlightRequests is gauge of the requests waiting for response
+	MetricInFlightRequests = "ignite_client_in_flight_requests"
+	// MetricOpenCursors is gauge of the query cursors opened on server and not closed yet
+	MetricOpenCursors = "ignite_client_open_cursors"
+)
+
+// MetricsSink receives client metrics, e.g. ExpvarSink or PrometheusSink of "metrics" package.
+// Sink must be thread safe, it is shared by all clients connected with the same ConnInfo.
+// Labels map must not be modified or retained after call.
+type MetricsSink interface {
+	// AddCounter adds non-negative delta to the counter
+	AddCounter(name string, labels map[string]string, delta float64)
+
+	// AddGauge adds delta (may be negative) to the gauge
+	AddGauge(name string, labels map[string]string, delta float64)
+
+	// ObserveHistogram adds value to the histogram
+	ObserveHistogram(name string, labels map[string]string, value float64)
+}
+
+// metricsInterceptor records metrics of the request to the sink of the operation connection
+func metricsInterceptor(info *OperationInfo, req Request, res Response, invoker Invoker) error {
+	c := info.conn
+	c.metrics.AddGauge(MetricInFlightRequests, nil, 1)
+	err := invoker(info, req, res)
+	c.metrics.AddGauge(MetricInFlightRequests, nil, -1)
+
+	if info.Handshake {
+		result := "error"
+		if err == nil {
+			result = "rejected"
+			if r, ok := res.(*ResponseHandshake); ok && r.Success {
+				result = "success"
+			}
+		}
+		c.metrics.AddCounter(MetricHandshakes, map[string]string{"result": result}, 1)
+		return err
+	}
+
+	op := map[string]string{"op": OpName(info.OpCode)}
+	status := "error"
+	if err == nil {
+		if r, ok := res.(*ResponseOperation); ok {
+			status = strconv.Itoa(int(r.Status))
+			c.trackCursors(info.OpCode, r)
+		} else {
+			status = strconv.Itoa(OperationStatusSuccess)
+		}
+	}
+	c.metrics.AddCounter(MetricOperations, map[string]string{"op": op["op"], "status": status}, 1)
+	c.metrics.ObserveHistogram(MetricOperationDuration, op, info.Duration.Seconds())
+	c.metrics.AddCounter(MetricSentBytes, op, float64(info.RequestSize))
+	c.metrics.AddCounter(MetricReceivedBytes, op, float64(info.ResponseSize))
+
+	return err
+}
+
+// trackCursors updates count of the open cursors by successful query, page and resource close responses.
+// Server closes cursor itself after the last page is returned.
+func (c *connection) trackCursors(code int16, r *ResponseOperation) {
+	if r.Status != OperationStatusSuccess {
+		return
+	}
+	var delta int64
+	switch code {
+	case OpQuerySQL, OpQuerySQLFields, OpQueryScan:
+		if responseHasMore(r) {
+			delta = 1
+		}
+	case OpQuerySQLCursorGetPage, OpQuerySQLFieldsCursorGetPage, OpQueryScanCursorGetPage:
+		if !responseHasMore(r) {
+			delta = -1
+		}
+	case OpResourceClose:
+		delta = -1
+	}
+	if delta != 0 {
+		atomic.AddInt64(&c.cursors, delta)
+		c.metrics.AddGauge(MetricOpenCursors, nil, float64(delta))
+	}
+}
+
+// responseHasMore returns value of the "has more" flag which is the last byte of the query and page responses
+func responseHasMore(r *ResponseOperation) bool {
+	m, ok := r.message.(*bytes.Reader)
+	if !ok || m.Size() == 0 {
+		return false
+	}
+	b := []byte{0}
+	if _, err := m.ReadAt(b, m.Size()-1); err != nil {
+		return false
+	}
+	return b[0] != 0
+}
+
+// metricsConnected records connection opened after successful handshake
+func (c *connection) metricsConnected() {
+	if c.metrics != nil {
+		atomic.StoreInt32(&c.metricsOpen, 1)
+		c.metrics.AddGauge(MetricOpenConnections, nil, 1)
+	}
+}
+
+// metricsClosed records connection closed, cursors of the connection are released by server
+func (c *connection) metricsClosed() {
+	if c.metrics != nil && atomic.CompareAndSwapInt32(&c.metricsOpen, 1, 0) {
+		c.metrics.AddGauge(MetricOpenConnections, nil, -1)
+		if n := atomic.SwapInt64(&c.cursors, 0); n > 0 {
+			c.metrics.AddGauge(MetricOpenCursors, nil, -float64(n))
+		}
+	}
+}
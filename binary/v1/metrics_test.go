@@ -0,0 +1,125 @@
+package ignite
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testSink stores sum of the values by name and labels
+type testSink struct {
+	mutex  sync.Mutex
+	values map[string]float64
+}
+
+func (s *testSink) add(name string, labels map[string]string, v float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var l []string
+	for _, n := range []string{"op", "status", "result"} {
+		if v, ok := labels[n]; ok {
+			l = append(l, n+"="+v)
+		}
+	}
+	s.values[name+"{"+strings.Join(l, ",")+"}"] += v
+}
+
+func (s *testSink) AddCounter(name string, labels map[string]string, delta float64) {
+	s.add(name, labels, delta)
+}
+
+func (s *testSink) AddGauge(name string, labels map[string]string, delta float64) {
+	s.add(name, labels, delta)
+}
+
+func (s *testSink) ObserveHistogram(name string, labels map[string]string, value float64) {
+	s.add(name+"_count", labels, 1)
+}
+
+func TestConnect_Metrics(t *testing.T) {
+	h := func(code int16, payload []byte) (int32, []byte) {
+		w := newRequest()
+		switch code {
+		case OpCacheGet:
+			WriteOString(&w, "value")
+		case OpQueryScan:
+			WriteLong(&w, 1)    // cursor ID
+			WriteInt(&w, 0)     // row count
+			WriteBool(&w, true) // has more
+		case OpQueryScanCursorGetPage:
+			WriteInt(&w, 0)
+			WriteBool(&w, false)
+		case OpCacheDestroy:
+			return 1000, nil
+		}
+		return OperationStatusSuccess, w.payload.Bytes()
+	}
+
+	sink := &testSink{values: map[string]float64{}}
+	c, err := Connect(ConnInfo{Network: "tcp", Host: "127.0.0.1", Port: 10800, Major: 1, Minor: 1, Patch: 0,
+		Dial: newTestDial(nil, h), Metrics: sink})
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	if _, err = c.CacheGet("TestCache", false, "key"); err != nil {
+		t.Fatalf("CacheGet() error = %v", err)
+	}
+	if err = c.CacheDestroy("TestCache"); err == nil {
+		t.Fatalf("CacheDestroy() error = nil, want cache does not exist error")
+	}
+	if _, err = c.QueryScan("TestCache", false, QueryScanData{PageSize: 1}); err != nil {
+		t.Fatalf("QueryScan() error = %v", err)
+	}
+	if sink.values["ignite_client_open_cursors{}"] != 1 {
+		t.Errorf("open cursors = %v, want 1", sink.values["ignite_client_open_cursors{}"])
+	}
+	if _, err = c.QueryScanCursorGetPage(1); err != nil {
+		t.Fatalf("QueryScanCursorGetPage() error = %v", err)
+	}
+	if sink.values["ignite_client_open_connections{}"] != 1 {
+		t.Errorf("open connections = %v, want 1", sink.values["ignite_client_open_connections{}"])
+	}
+	c.Close()
+	c.Close()
+
+	want := map[string]float64{
+		"ignite_client_handshakes_total{result=success}":                            1,
+		"ignite_client_operations_total{op=OP_CACHE_GET,status=0}":                  1,
+		"ignite_client_operations_total{op=OP_CACHE_DESTROY,status=1000}":           1,
+		"ignite_client_operations_total{op=OP_QUERY_SCAN,status=0}":                 1,
+		"ignite_client_operations_total{op=OP_QUERY_SCAN_CURSOR_GET_PAGE,status=0}": 1,
+		"ignite_client_operation_duration_seconds_count{op=OP_CACHE_GET}":           1,
+		"ignite_client_open_connections{}":                                          0,
+		"ignite_client_open_cursors{}":                                              0,
+		"ignite_client_in_flight_requests{}":                                        0,
+	}
+	for k, v := range want {
+		if got, ok := sink.values[k]; !ok || got != v {
+			t.Errorf("metric %s = %v, want %v", k, got, v)
+		}
+	}
+	if sink.values["ignite_client_sent_bytes_total{op=OP_CACHE_GET}"] <= 0 ||
+		sink.values["ignite_client_received_bytes_total{op=OP_CACHE_GET}"] <= 0 {
+		t.Errorf("sent and received bytes are not recorded: %v", sink.values)
+	}
+}
+
+func TestOpName(t *testing.T) {
+	tests := []struct {
+		name string
+		code int16
+		want string
+	}{
+		{name: "1", code: OpCacheGet, want: "OP_CACHE_GET"},
+		{name: "2", code: OpQuerySQLFieldsCursorGetPage, want: "OP_QUERY_SQL_FIELDS_CURSOR_GET_PAGE"},
+		{name: "3", code: 12345, want: "OP_12345"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OpName(tt.code); got != tt.want {
+				t.Errorf("OpName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package ignite
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// DefaultNearCacheMaxSize is default count of entries kept by near cache
+const DefaultNearCacheMaxSize = 10000
+
+// NearCacheOptions contains options of near cache
+type NearCacheOptions struct {
+	// Caches is names of the caches to keep near.
+	// Empty value means all the caches.
+	Caches []string
+
+	// MaxSize is count of entries kept by near cache (for all the caches).
+	// Least recently used entries are evicted when the limit is reached.
+	// Zero value means DefaultNearCacheMaxSize.
+	MaxSize int
+
+	// TTL is time to live of the near cache entry.
+	// Zero value means the entry lives until it is evicted or invalidated.
+	TTL time.Duration
+}
+
+// NearCacheStats contains near cache statistics
+type NearCacheStats struct {
+	// Hits is count of values found in near cache
+	Hits int64
+
+	// Misses is count of values requested from server
+	Misses int64
+
+	// Evictions is count of entries removed because of size limit or TTL
+	Evictions int64
+
+	// Size is current count of entries
+	Size int
+}
+
+// NearCacheClient is client with near cache in front of Key-Value Queries.
+//
+// Values got by CacheGet and CacheGetAll are kept in process memory
+// and returned without server request until they are evicted.
+// Entries are invalidated by Key-Value Queries of the client (and views created by WithExpiryPolicy and WithRetryPolicy),
+// but not by SQL queries and other clients. Use NearCacheInvalidate to invalidate
+// the entries changed outside, e.g. from handler of server-side change events.
+//
+// Values are shared, so they must not be changed by caller.
+type NearCacheClient interface {
+	Client
+
+	// NearCacheInvalidate removes the entry from near cache.
+	NearCacheInvalidate(cache string, key interface{})
+
+	// NearCacheInvalidateAll removes all the entries of the cache from near cache.
+	NearCacheInvalidateAll(cache string)
+
+	// NearCacheStats returns near cache statistics.
+	NearCacheStats() NearCacheStats
+}
+
+type nearCacheKey struct {
+	cache  string
+	binary bool
+	key    interface{}
+}
+
+type nearCacheEntry struct {
+	key     nearCacheKey
+	value   interface{}
+	expires time.Time
+}
+
+// nearCacheStore is LRU storage shared by near cache client and its views
+type nearCacheStore struct {
+	caches  map[string]bool
+	maxSize int
+	ttl     time.Duration
+
+	mutex   sync.Mutex
+	lru     *list.List
+	entries map[nearCacheKey]*list.Element
+	// generation is incremented by invalidation, so concurrent read does not store stale value
+	generation uint64
+	stats      NearCacheStats
+}
+
+type nearCacheClient struct {
+	Client
+	store *nearCacheStore
+}
+
+// NewNearCache returns client with near cache in front of Key-Value Queries of the client.
+func NewNearCache(c Client, opts NearCacheOptions) NearCacheClient {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultNearCacheMaxSize
+	}
+	s := &nearCacheStore{
+		maxSize: opts.MaxSize,
+		ttl:     opts.TTL,
+		lru:     list.New(),
+		entries: map[nearCacheKey]*list.Element{},
+	}
+	if len(opts.Caches) > 0 {
+		s.caches = make(map[string]bool, len(opts.Caches))
+		for _, name := range opts.Caches {
+			s.caches[name] = true
+		}
+	}
+	return &nearCacheClient{Client: c, store: s}
+}
+
+// cacheable returns true if the entry can be kept in near cache
+func (s *nearCacheStore) cacheable(cache string, key interface{}) bool {
+	if s.caches != nil && !s.caches[cache] {
+		return false
+	}
+	return key != nil && reflect.TypeOf(key).Comparable()
+}
+
+// get returns value from near cache
+func (s *nearCacheStore) get(k nearCacheKey) (interface{}, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	e, ok := s.entries[k]
+	if !ok {
+		s.stats.Misses++
+		return nil, false
+	}
+	entry := e.Value.(*nearCacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		s.remove(e)
+		s.stats.Evictions++
+		s.stats.Misses++
+		return nil, false
+	}
+	s.lru.MoveToFront(e)
+	s.stats.Hits++
+	return entry.value, true
+}
+
+// currentGeneration returns generation to be passed to put
+func (s *nearCacheStore) currentGeneration() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.generation
+}
+
+// put stores value got from server if there were no invalidations since generation
+func (s *nearCacheStore) put(k nearCacheKey, value interface{}, generation uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if generation != s.generation {
+		return
+	}
+	var expires time.Time
+	if s.ttl > 0 {
+		expires = time.Now().Add(s.ttl)
+	}
+	if e, ok := s.entries[k]; ok {
+		entry := e.Value.(*nearCacheEntry)
+		entry.value = value
+		entry.expires = expires
+		s.lru.MoveToFront(e)
+		return
+	}
+	s.entries[k] = s.lru.PushFront(&nearCacheEntry{key: k, value: value, expires: expires})
+	for s.lru.Len() > s.maxSize {
+		s.remove(s.lru.Back())
+		s.stats.Evictions++
+	}
+}
+
+// invalidate removes entries of the keys
+func (s *nearCacheStore) invalidate(cache string, keys ...interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.generation++
+	for _, key := range keys {
+		if key == nil || !reflect.TypeOf(key).Comparable() {
+			continue
+		}
+		for _, binary := range []bool{false, true} {
+			if e, ok := s.entries[nearCacheKey{cache: cache, binary: binary, key: key}]; ok {
+				s.remove(e)
+			}
+		}
+	}
+}
+
+// invalidateAll removes all the entries of the cache
+func (s *nearCacheStore) invalidateAll(cache string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.generation++
+	for k, e := range s.entries {
+		if k.cache == cache {
+			s.remove(e)
+		}
+	}
+}
+
+// remove removes entry.
+// Must be called under lock.
+func (s *nearCacheStore) remove(e *list.Element) {
+	s.lru.Remove(e)
+	delete(s.entries, e.Value.(*nearCacheEntry).key)
+}
+
+// NearCacheInvalidate removes the entry from near cache.
+func (c *nearCacheClient) NearCacheInvalidate(cache string, key interface{}) {
+	c.store.invalidate(cache, key)
+}
+
+// NearCacheInvalidateAll removes all the entries of the cache from near cache.
+func (c *nearCacheClient) NearCacheInvalidateAll(cache string) {
+	c.store.invalidateAll(cache)
+}
+
+// NearCacheStats returns near cache statistics.
+func (c *nearCacheClient) NearCacheStats() NearCacheStats {
+	c.store.mutex.Lock()
+	defer c.store.mutex.Unlock()
+
+	stats := c.store.stats
+	stats.Size = c.store.lru.Len()
+	return stats
+}
+
+// WithExpiryPolicy returns view of the client which applies expiry policy to Key-Value Queries.
+// The view shares near cache with the client.
+func (c *nearCacheClient) WithExpiryPolicy(p ExpiryPolicy) (Client, error) {
+	v, err := c.Client.WithExpiryPolicy(p)
+	if err != nil {
+		return nil, err
+	}
+	return &nearCacheClient{Client: v, store: c.store}, nil
+}
+
+// WithRetryPolicy returns view of the client which uses the retry policy instead of ConnInfo.Retry.
+// The view shares near cache with the client.
+func (c *nearCacheClient) WithRetryPolicy(p RetryPolicy) Client {
+	return &nearCacheClient{Client: c.Client.WithRetryPolicy(p), store: c.store}
+}
+
+// CacheDestroy destroys cache with a given name.
+func (c *nearCacheClient) CacheDestroy(cache string) error {
+	defer c.store.invalidateAll(cache)
+	return c.Client.CacheDestroy(cache)
+}
+
+// CacheGet retrieves a value from near cache or from server by key.
+func (c *nearCacheClient) CacheGet(cache string, binary bool, key interface{}) (interface{}, error) {
+	if !c.store.cacheable(cache, key) {
+		return c.Client.CacheGet(cache, binary, key)
+	}
+	k := nearCacheKey{cache: cache, binary: binary, key: key}
+	if v, ok := c.store.get(k); ok {
+		return v, nil
+	}
+	g := c.store.currentGeneration()
+	v, err := c.Client.CacheGet(cache, binary, key)
+	if err != nil {
+		return nil, err
+	}
+	if v != nil {
+		c.store.put(k, v, g)
+	}
+	return v, nil
+}
+
+// CacheGetAll retrieves multiple key-value pairs from near cache or from server.
+func (c *nearCacheClient) CacheGetAll(cache string, binary bool, keys []interface{}) (map[interface{}]interface{}, error) {
+	data := map[interface{}]interface{}{}
+	missed := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		if c.store.cacheable(cache, key) {
+			if v, ok := c.store.get(nearCacheKey{cache: cache, binary: binary, key: key}); ok {
+				data[key] = v
+				continue
+			}
+		}
+		missed = append(missed, key)
+	}
+	if len(missed) == 0 {
+		return data, nil
+	}
+
+	g := c.store.currentGeneration()
+	res, err := c.Client.CacheGetAll(cache, binary, missed)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range res {
+		data[k] = v
+		if v != nil && c.store.cacheable(cache, k) {
+			c.store.put(nearCacheKey{cache: cache, binary: binary, key: k}, v, g)
+		}
+	}
+	return data, nil
+}
+
+// CacheContainsKey returns a value indicating whether given key is present in cache.
+func (c *nearCacheClient) CacheContainsKey(cache string, binary bool, key interface{}) (bool, error) {
+	if c.store.cacheable(cache, key) {
+		if _, ok := c.store.get(nearCacheKey{cache: cache, binary: binary, key: key}); ok {
+			return true, nil
+		}
+	}
+	return c.Client.CacheContainsKey(cache, binary, key)
+}
+
+// CachePut puts a value with a given key to cache (overwriting existing value if any).
+func (c *nearCacheClient) CachePut(cache string, binary bool, key interface{}, value interface{}) error {
+	defer c.store.invalidate(cache, key)
+	return c.Client.CachePut(cache, binary, key, value)
+}
+
+// CachePutAll puts a value with a given key to cache (overwriting existing value if any).
+func (c *nearCacheClient) CachePutAll(cache string, binary bool, data map[interface{}]interface{}) error {
+	defer func() {
+		keys := make([]interface{}, 0, len(data))
+		for k := range data {
+			keys = append(keys, k)
+		}
+		c.store.invalidate(cache, keys...)
+	}()
+	return c.Client.CachePutAll(cache, binary, data)
+}
+
+// CacheGetAndPut puts a value with a given key to cache, and returns the previous value for that key.
+func (c *nearCacheClient) CacheGetAndPut(cache string, binary bool, key interface{}, value interface{}) (interface{}, error) {
+	defer c.store.invalidate(cache, key)
+	return c.Client.CacheGetAndPut(cache, binary, key, value)
+}
+
+// CacheGetAndReplace puts a value with a given key to cache, returning previous value for that key,
+// if and only if there is a value currently mapped for that key.
+func (c *nearCacheClient) CacheGetAndReplace(cache string, binary bool, key interface{}, value interface{}) (interface{}, error) {
+	defer c.store.invalidate(cache, key)
+	return c.Client.CacheGetAndReplace(cache, binary, key, value)
+}
+
+// CacheGetAndRemove removes the cache entry with specified key, returning the value.
+func (c *nearCacheClient) CacheGetAndRemove(cache string, binary bool, key interface{}) (interface{}, error) {
+	defer c.store.invalidate(cache, key)
+	return c.Client.CacheGetAndRemove(cache, binary, key)
+}
+
+// CachePutIfAbsent puts a value with a given key to cache only if the key does not already exist.
+func (c *nearCacheClient) CachePutIfAbsent(cache string, binary bool, key interface{}, value interface{}) (bool, error) {
+	defer c.store.invalidate(cache, key)
+	return c.Client.CachePutIfAbsent(cache, binary, key, value)
+}
+
+// CacheGetAndPutIfAbsent puts a value with a given key to cache only if the key does not already exist.
+func (c *nearCacheClient) CacheGetAndPutIfAbsent(cache string, binary bool, key interface{}, value interface{}) (interface{}, error) {
+	defer c.store.invalidate(cache, key)
+	return c.Client.CacheGetAndPutIfAbsent(cache, binary, key, value)
+}
+
+// CacheReplace puts a value with a given key to cache only if the key already exists.
+func (c *nearCacheClient) CacheReplace(cache string, binary bool, key interface{}, value interface{}) (bool, error) {
+	defer c.store.invalidate(cache, key)
+	return c.Client.CacheReplace(cache, binary, key, value)
+}
+
+// CacheReplaceIfEquals puts a value with a given key to cache only if
+// the key already exists and value equals provided value.
+func (c *nearCacheClient) CacheReplaceIfEquals(cache string, binary bool, key interface{}, valueCompare interface{}, valueNew interface{}) (bool, error) {
+	defer c.store.invalidate(cache, key)
+	return c.Client.CacheReplaceIfEquals(cache, binary, key, valueCompare, valueNew)
+}
+
+// CacheClear clears the cache without notifying listeners or cache writers.
+func (c *nearCacheClient) CacheClear(cache string, binary bool) error {
+	defer c.store.invalidateAll(cache)
+	return c.Client.CacheClear(cache, binary)
+}
+
+// CacheClearKey clears the cache key without notifying listeners or cache writers.
+func (c *nearCacheClient) CacheClearKey(cache string, binary bool, key interface{}) error {
+	defer c.store.invalidate(cache, key)
+	return c.Client.CacheClearKey(cache, binary, key)
+}
+
+// CacheClearKeys clears the cache keys without notifying listeners or cache writers.
+func (c *nearCacheClient) CacheClearKeys(cache string, binary bool, keys []interface{}) error {
+	defer c.store.invalidate(cache, keys...)
+	return c.Client.CacheClearKeys(cache, binary, keys)
+}
+
+// CacheRemoveKey removes an entry with a given key, notifying listeners and cache writers.
+func (c *nearCacheClient) CacheRemoveKey(cache string, binary bool, key interface{}) (bool, error) {
+	defer c.store.invalidate(cache, key)
+	return c.Client.CacheRemoveKey(cache, binary, key)
+}
+
+// CacheRemoveIfEquals removes an entry with a given key if provided value is equal to actual value,
+// notifying listeners and cache writers.
+func (c *nearCacheClient) CacheRemoveIfEquals(cache string, binary bool, key interface{}, value interface{}) (bool, error) {
+	defer c.store.invalidate(cache, key)
+	return c.Client.CacheRemoveIfEquals(cache, binary, key, value)
+}
+
+// CacheRemoveKeys removes entries with given keys, notifying listeners and cache writers.
+func (c *nearCacheClient) CacheRemoveKeys(cache string, binary bool, keys []interface{}) error {
+	defer c.store.invalidate(cache, keys...)
+	return c.Client.CacheRemoveKeys(cache, binary, keys)
+}
+
+// CacheRemoveAll removes all entries from cache, notifying listeners and cache writers.
+func (c *nearCacheClient) CacheRemoveAll(cache string, binary bool) error {
+	defer c.store.invalidateAll(cache)
+	return c.Client.CacheRemoveAll(cache, binary)
+}
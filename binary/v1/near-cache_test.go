@@ -0,0 +1,130 @@
+package ignite
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newNearCacheTestClient(t *testing.T, gets *int32) *client {
+	return newTestClient(t, ProtocolVersion110, nil, func(code int16, payload []byte) (int32, []byte) {
+		switch code {
+		case OpCacheGet:
+			atomic.AddInt32(gets, 1)
+			w := newRequest()
+			WriteObject(&w, "value")
+			return OperationStatusSuccess, w.payload.Bytes()
+		case OpCachePut:
+			return OperationStatusSuccess, nil
+		default:
+			return 2, nil
+		}
+	})
+}
+
+func Test_nearCacheClient_CacheGet(t *testing.T) {
+	var gets int32
+	c := NewNearCache(newNearCacheTestClient(t, &gets), NearCacheOptions{MaxSize: 2})
+	defer c.Close()
+
+	get := func(key interface{}) {
+		v, err := c.CacheGet("TestCache", false, key)
+		if err != nil {
+			t.Fatalf("CacheGet() error = %v", err)
+		}
+		if v != "value" {
+			t.Fatalf("CacheGet() = %v, want \"value\"", v)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		action func()
+		want   NearCacheStats
+		gets   int32
+	}{
+		{
+			name:   "miss",
+			action: func() { get(int32(1)) },
+			want:   NearCacheStats{Misses: 1, Size: 1},
+			gets:   1,
+		},
+		{
+			name:   "hit",
+			action: func() { get(int32(1)) },
+			want:   NearCacheStats{Hits: 1, Misses: 1, Size: 1},
+			gets:   1,
+		},
+		{
+			name: "invalidated by put",
+			action: func() {
+				if err := c.CachePut("TestCache", false, int32(1), "value"); err != nil {
+					t.Fatalf("CachePut() error = %v", err)
+				}
+				get(int32(1))
+			},
+			want: NearCacheStats{Hits: 1, Misses: 2, Size: 1},
+			gets: 2,
+		},
+		{
+			name: "evicted",
+			action: func() {
+				get(int32(2))
+				get(int32(3))
+				get(int32(1))
+			},
+			want: NearCacheStats{Hits: 1, Misses: 5, Evictions: 2, Size: 2},
+			gets: 5,
+		},
+		{
+			name: "invalidated by user",
+			action: func() {
+				c.NearCacheInvalidateAll("TestCache")
+				get(int32(3))
+			},
+			want: NearCacheStats{Hits: 1, Misses: 6, Evictions: 2, Size: 1},
+			gets: 6,
+		},
+		{
+			name:   "unsupported key",
+			action: func() { get([]byte{1}) },
+			want:   NearCacheStats{Hits: 1, Misses: 6, Evictions: 2, Size: 1},
+			gets:   7,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action()
+			if got := c.NearCacheStats(); got != tt.want {
+				t.Errorf("NearCacheStats() = %+v, want %+v", got, tt.want)
+			}
+			if n := atomic.LoadInt32(&gets); n != tt.gets {
+				t.Errorf("OP_CACHE_GET count = %d, want %d", n, tt.gets)
+			}
+		})
+	}
+}
+
+func Test_nearCacheClient_TTL(t *testing.T) {
+	var gets int32
+	c := NewNearCache(newNearCacheTestClient(t, &gets), NearCacheOptions{TTL: 20 * time.Millisecond})
+	defer c.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.CacheGet("TestCache", false, "key"); err != nil {
+			t.Fatalf("CacheGet() error = %v", err)
+		}
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, err := c.CacheGet("TestCache", false, "key"); err != nil {
+		t.Fatalf("CacheGet() error = %v", err)
+	}
+
+	want := NearCacheStats{Hits: 1, Misses: 2, Evictions: 1, Size: 1}
+	if got := c.NearCacheStats(); got != want {
+		t.Errorf("NearCacheStats() = %+v, want %+v", got, want)
+	}
+	if n := atomic.LoadInt32(&gets); n != 2 {
+		t.Errorf("OP_CACHE_GET count = %d, want 2", n)
+	}
+}
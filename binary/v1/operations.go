@@ -1,6 +1,15 @@
 package ignite
 
+import "strconv"
+
 const (
+	// Connection
+
+	// OpHeartbeat keeps connection alive.
+	OpHeartbeat = 4
+	// OpGetIdleTimeout gets idle timeout of the connection configured on server.
+	OpGetIdleTimeout = 5
+
 	// Cache Configuration
 
 	// OpCacheGetNames gets existing cache names.
@@ -84,4 +93,71 @@ const (
 	OpQueryScanCursorGetPage = 2001
 	// OpResourceClose closes a resource, such as query cursor.
 	OpResourceClose = 0
+
+	// Cluster Group
+
+	// OpClusterGroupGetNodeIDs gets IDs of the cluster nodes matching the projection.
+	OpClusterGroupGetNodeIDs = 5100
+	// OpClusterGroupGetNodeInfo gets information about the given cluster nodes.
+	OpClusterGroupGetNodeInfo = 5101
+
+	// Services
+
+	// OpServiceInvoke invokes a method of Ignite service.
+	OpServiceInvoke = 7000
+	// OpServiceGetDescriptors gets descriptors of all deployed services.
+	OpServiceGetDescriptors = 7001
 )
+
+var opNames = map[int16]string{
+	OpHeartbeat:                         "OP_HEARTBEAT",
+	OpGetIdleTimeout:                    "OP_GET_IDLE_TIMEOUT",
+	OpCacheGetNames:                     "OP_CACHE_GET_NAMES",
+	OpCacheCreateWithName:               "OP_CACHE_CREATE_WITH_NAME",
+	OpCacheGetOrCreateWithName:          "OP_CACHE_GET_OR_CREATE_WITH_NAME",
+	OpCacheCreateWithConfiguration:      "OP_CACHE_CREATE_WITH_CONFIGURATION",
+	OpCacheGetOrCreateWithConfiguration: "OP_CACHE_GET_OR_CREATE_WITH_CONFIGURATION",
+	OpCacheGetConfiguration:             "OP_CACHE_GET_CONFIGURATION",
+	OpCacheDestroy:                      "OP_CACHE_DESTROY",
+	OpCacheGet:                          "OP_CACHE_GET",
+	OpCachePut:                          "OP_CACHE_PUT",
+	OpCachePutIfAbsent:                  "OP_CACHE_PUT_IF_ABSENT",
+	OpCacheGetAll:                       "OP_CACHE_GET_ALL",
+	OpCachePutAll:                       "OP_CACHE_PUT_ALL",
+	OpCacheGetAndPut:                    "OP_CACHE_GET_AND_PUT",
+	OpCacheGetAndReplace:                "OP_CACHE_GET_AND_REPLACE",
+	OpCacheGetAndRemove:                 "OP_CACHE_GET_AND_REMOVE",
+	OpCacheGetAndPutIfAbsent:            "OP_CACHE_GET_AND_PUT_IF_ABSENT",
+	OpCacheReplace:                      "OP_CACHE_REPLACE",
+	OpCacheReplaceIfEquals:              "OP_CACHE_REPLACE_IF_EQUALS",
+	OpCacheContainsKey:                  "OP_CACHE_CONTAINS_KEY",
+	OpCacheContainsKeys:                 "OP_CACHE_CONTAINS_KEYS",
+	OpCacheClear:                        "OP_CACHE_CLEAR",
+	OpCacheClearKey:                     "OP_CACHE_CLEAR_KEY",
+	OpCacheClearKeys:                    "OP_CACHE_CLEAR_KEYS",
+	OpCacheRemoveKey:                    "OP_CACHE_REMOVE_KEY",
+	OpCacheRemoveIfEquals:               "OP_CACHE_REMOVE_IF_EQUALS",
+	OpCacheRemoveKeys:                   "OP_CACHE_REMOVE_KEYS",
+	OpCacheRemoveAll:                    "OP_CACHE_REMOVE_ALL",
+	OpCacheGetSize:                      "OP_CACHE_GET_SIZE",
+	OpQuerySQL:                          "OP_QUERY_SQL",
+	OpQuerySQLCursorGetPage:             "OP_QUERY_SQL_CURSOR_GET_PAGE",
+	OpQuerySQLFields:                    "OP_QUERY_SQL_FIELDS",
+	OpQuerySQLFieldsCursorGetPage:       "OP_QUERY_SQL_FIELDS_CURSOR_GET_PAGE",
+	OpQueryScan:                         "OP_QUERY_SCAN",
+	OpQueryScanCursorGetPage:            "OP_QUERY_SCAN_CURSOR_GET_PAGE",
+	OpResourceClose:                     "OP_RESOURCE_CLOSE",
+	OpClusterGroupGetNodeIDs:            "OP_CLUSTER_GROUP_GET_NODE_IDS",
+	OpClusterGroupGetNodeInfo:           "OP_CLUSTER_GROUP_GET_NODE_INFO",
+	OpServiceInvoke:                     "OP_SERVICE_INVOKE",
+	OpServiceGetDescriptors:             "OP_SERVICE_GET_DESCRIPTORS",
+}
+
+// OpName returns name of the operation, e.g. "OP_CACHE_GET".
+// Returns "OP_<code>" for unknown (custom) operations.
+func OpName(code int16) string {
+	if name, ok := opNames[code]; ok {
+		return name
+	}
+	return "OP_" + strconv.Itoa(int(code))
+}
@@ -0,0 +1,112 @@
+package ignite
+
+import (
+	"fmt"
+)
+
+// ProtocolVersion is binary protocol version
+type ProtocolVersion struct {
+	Major, Minor, Patch int
+}
+
+var (
+	// ProtocolVersion110 adds authentication
+	ProtocolVersion110 = ProtocolVersion{Major: 1, Minor: 1, Patch: 0}
+	// ProtocolVersion140 adds flags to operation response header and node ID to handshake response
+	ProtocolVersion140 = ProtocolVersion{Major: 1, Minor: 4, Patch: 0}
+	// ProtocolVersion160 adds expiry policy
+	ProtocolVersion160 = ProtocolVersion{Major: 1, Minor: 6, Patch: 0}
+	// ProtocolVersion170 adds bitmap of features to handshake
+	ProtocolVersion170 = ProtocolVersion{Major: 1, Minor: 7, Patch: 0}
+)
+
+// Compare returns an integer comparing two versions.
+// The result will be 0 if v==o, -1 if v < o, and +1 if v > o.
+func (v ProtocolVersion) Compare(o ProtocolVersion) int {
+	switch {
+	case v.Major != o.Major:
+		return compareInt(v.Major, o.Major)
+	case v.Minor != o.Minor:
+		return compareInt(v.Minor, o.Minor)
+	default:
+		return compareInt(v.Patch, o.Patch)
+	}
+}
+
+// AtLeast returns true if version is equal or greater than provided one
+func (v ProtocolVersion) AtLeast(o ProtocolVersion) bool {
+	return v.Compare(o) >= 0
+}
+
+// String returns version in "vMajor.Minor.Patch" format
+func (v ProtocolVersion) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+func compareInt(a, b int) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+const (
+	// Protocol features negotiated by handshake (protocol v1.7.0+).
+	// Feature value is bit index in the features bitmap.
+
+	// FeatureUserAttributes is USER_ATTRIBUTES
+	FeatureUserAttributes = 0
+	// FeatureExecuteTaskByName is EXECUTE_TASK_BY_NAME
+	FeatureExecuteTaskByName = 1
+	// FeatureClusterStates is CLUSTER_STATES
+	FeatureClusterStates = 2
+	// FeatureClusterGroupGetNodesEndpoints is CLUSTER_GROUP_GET_NODES_ENDPOINTS
+	FeatureClusterGroupGetNodesEndpoints = 3
+	// FeatureClusterGroups is CLUSTER_GROUPS
+	FeatureClusterGroups = 4
+	// FeatureServiceInvoke is SERVICE_INVOKE
+	FeatureServiceInvoke = 5
+	// FeatureDefaultQueryTimeout is DEFAULT_QRY_TIMEOUT
+	FeatureDefaultQueryTimeout = 6
+	// FeatureQueryPartitionsBatchSize is QRY_PARTITIONS_BATCH_SIZE
+	FeatureQueryPartitionsBatchSize = 7
+	// FeatureBinaryConfiguration is BINARY_CONFIGURATION
+	FeatureBinaryConfiguration = 8
+	// FeatureGetServiceDescriptors is GET_SERVICE_DESCRIPTORS
+	FeatureGetServiceDescriptors = 9
+	// FeatureServiceInvokeCallContext is SERVICE_INVOKE_CALLCTX
+	FeatureServiceInvokeCallContext = 10
+	// FeatureHeartbeat is HEARTBEAT
+	FeatureHeartbeat = 11
+)
+
+// clientFeatures is the list of features supported by this client
+var clientFeatures = newFeatures(
+	FeatureClusterGroups,
+	FeatureServiceInvoke,
+	FeatureGetServiceDescriptors,
+	FeatureHeartbeat,
+)
+
+// newFeatures creates features bitmap
+func newFeatures(features ...int) []byte {
+	var b []byte
+	for _, f := range features {
+		for len(b) <= f/8 {
+			b = append(b, 0)
+		}
+		b[f/8] |= 1 << uint(f%8)
+	}
+	return b
+}
+
+// hasFeature returns true if feature is set in features bitmap
+func hasFeature(features []byte, feature int) bool {
+	if feature < 0 || feature/8 >= len(features) {
+		return false
+	}
+	return features[feature/8]&(1<<uint(feature%8)) != 0
+}
@@ -0,0 +1,40 @@
+package ignite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProtocolVersion_Compare(t *testing.T) {
+	tests := []struct {
+		name string
+		v    ProtocolVersion
+		o    ProtocolVersion
+		want int
+	}{
+		{name: "equal", v: ProtocolVersion{1, 1, 0}, o: ProtocolVersion{1, 1, 0}, want: 0},
+		{name: "less major", v: ProtocolVersion{0, 9, 9}, o: ProtocolVersion{1, 0, 0}, want: -1},
+		{name: "greater minor", v: ProtocolVersion{1, 7, 0}, o: ProtocolVersion{1, 4, 0}, want: 1},
+		{name: "less patch", v: ProtocolVersion{1, 7, 0}, o: ProtocolVersion{1, 7, 1}, want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.Compare(tt.o); got != tt.want {
+				t.Errorf("ProtocolVersion.Compare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newFeatures(t *testing.T) {
+	f := newFeatures(FeatureClusterGroups, FeatureHeartbeat)
+	if want := []byte{0x10, 0x08}; !reflect.DeepEqual(f, want) {
+		t.Errorf("newFeatures() = %#v, want %#v", f, want)
+	}
+	if !hasFeature(f, FeatureClusterGroups) || !hasFeature(f, FeatureHeartbeat) {
+		t.Errorf("hasFeature() = false, want true")
+	}
+	if hasFeature(f, FeatureServiceInvoke) || hasFeature(f, 100) || hasFeature(nil, FeatureClusterGroups) {
+		t.Errorf("hasFeature() = true, want false")
+	}
+}
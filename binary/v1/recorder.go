@@ -0,0 +1,160 @@
+package ignite
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// FrameDirectionRequest is direction of the frame sent by client
+	FrameDirectionRequest = "request"
+	// FrameDirectionResponse is direction of the frame sent by server
+	FrameDirectionResponse = "response"
+)
+
+// RecordedFrame is request or response frame captured by traffic recorder.
+// Recording is JSON lines, one frame per line.
+type RecordedFrame struct {
+	// Conn is ID of the connection the frame belongs to
+	Conn int64 `json:"conn"`
+
+	// Time the frame was sent or received
+	Time time.Time `json:"time"`
+
+	// Direction is FrameDirectionRequest or FrameDirectionResponse
+	Direction string `json:"direction"`
+
+	// Handshake is true for handshake request and response
+	Handshake bool `json:"handshake,omitempty"`
+
+	// OpCode is operation code (response frame has the code of the request with the same UID)
+	OpCode int16 `json:"op,omitempty"`
+
+	// UID is request ID
+	UID int64 `json:"uid,omitempty"`
+
+	// Data is frame without length prefix
+	Data []byte `json:"data"`
+}
+
+// recorderConnID is used to generate IDs of the recorded connections
+var recorderConnID int64
+
+// TrafficRecorder records request and response frames of the connections to the writer.
+// TrafficRecorder may be shared by several connections.
+type TrafficRecorder struct {
+	mutex sync.Mutex
+	enc   *json.Encoder
+}
+
+// NewTrafficRecorder creates recorder which writes frames to w in JSON lines format
+func NewTrafficRecorder(w io.Writer) *TrafficRecorder {
+	return &TrafficRecorder{enc: json.NewEncoder(w)}
+}
+
+func (r *TrafficRecorder) write(f RecordedFrame) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	// recording must not break the connection, so error is ignored
+	_ = r.enc.Encode(f)
+}
+
+// frameSplitter splits byte stream to length prefixed frames
+type frameSplitter struct {
+	buf []byte
+}
+
+// write appends bytes and returns completed frames
+func (s *frameSplitter) write(b []byte) [][]byte {
+	s.buf = append(s.buf, b...)
+	var frames [][]byte
+	for len(s.buf) >= 4 {
+		l := int(int32(binary.LittleEndian.Uint32(s.buf)))
+		if l < 0 || len(s.buf) < 4+l {
+			break
+		}
+		frames = append(frames, append([]byte{}, s.buf[4:4+l]...))
+		s.buf = s.buf[4+l:]
+	}
+	return frames
+}
+
+// recordingConn is net.Conn wrapper which records request and response frames
+type recordingConn struct {
+	net.Conn
+
+	id       int64
+	recorder *TrafficRecorder
+
+	// mutex protects the fields below
+	mutex     sync.Mutex
+	requests  frameSplitter
+	responses frameSplitter
+	// handshake is true until the response to handshake request is recorded
+	handshake bool
+	// ops is operation codes of the requests waiting for response
+	ops map[int64]int16
+}
+
+// newRecordingConn returns connection which records traffic with the recorder
+func newRecordingConn(conn net.Conn, r *TrafficRecorder) net.Conn {
+	return &recordingConn{
+		Conn:      conn,
+		id:        atomic.AddInt64(&recorderConnID, 1),
+		recorder:  r,
+		handshake: true,
+		ops:       map[int64]int16{},
+	}
+}
+
+// Write writes data to the connection and records completed request frames
+func (c *recordingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.record(FrameDirectionRequest, b[:n])
+	return n, err
+}
+
+// Read reads data from the connection and records completed response frames
+func (c *recordingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.record(FrameDirectionResponse, b[:n])
+	return n, err
+}
+
+func (c *recordingConn) record(direction string, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var frames [][]byte
+	if direction == FrameDirectionRequest {
+		frames = c.requests.write(b)
+	} else {
+		frames = c.responses.write(b)
+	}
+	for _, data := range frames {
+		f := RecordedFrame{Conn: c.id, Time: time.Now(), Direction: direction, Handshake: c.handshake, Data: data}
+		if !c.handshake {
+			if direction == FrameDirectionRequest && len(data) >= 10 {
+				f.OpCode = int16(binary.LittleEndian.Uint16(data))
+				f.UID = int64(binary.LittleEndian.Uint64(data[2:]))
+				c.ops[f.UID] = f.OpCode
+			} else if direction == FrameDirectionResponse && len(data) >= 8 {
+				f.UID = int64(binary.LittleEndian.Uint64(data))
+				f.OpCode = c.ops[f.UID]
+				delete(c.ops, f.UID)
+			}
+		} else if direction == FrameDirectionResponse {
+			c.handshake = false
+		}
+		c.recorder.write(f)
+	}
+}
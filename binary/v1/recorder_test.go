@@ -0,0 +1,131 @@
+package ignite
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+func Test_recordAndReplay(t *testing.T) {
+	h := func(code int16, payload []byte) (int32, []byte) {
+		switch code {
+		case OpCacheGet:
+			w := newRequest()
+			WriteOString(&w, "value")
+			return OperationStatusSuccess, w.payload.Bytes()
+		case OpCachePut:
+			return OperationStatusSuccess, nil
+		default:
+			return 1, nil
+		}
+	}
+
+	// record
+	recording := &bytes.Buffer{}
+	c, err := Connect(ConnInfo{Network: "tcp", Host: "::1", Port: 10800, Major: 1, Minor: 7, Patch: 0,
+		Dial: newTestDial(newFeatures(FeatureHeartbeat), h), Recorder: NewTrafficRecorder(recording)})
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if err = c.CachePut("TestCache", false, "key", "value"); err != nil {
+		t.Fatalf("CachePut() error = %v", err)
+	}
+	if v, err := c.CacheGet("TestCache", false, "key"); err != nil || v != "value" {
+		t.Fatalf("CacheGet() = %v, %v, want \"value\"", v, err)
+	}
+	c.Close()
+
+	// check recording
+	var frames []RecordedFrame
+	dec := json.NewDecoder(bytes.NewReader(recording.Bytes()))
+	for dec.More() {
+		var f RecordedFrame
+		if err := dec.Decode(&f); err != nil {
+			t.Fatalf("failed to decode recorded frame: %v", err)
+		}
+		frames = append(frames, f)
+	}
+	want := []struct {
+		direction string
+		handshake bool
+		op        int16
+	}{
+		{FrameDirectionRequest, true, 0},
+		{FrameDirectionResponse, true, 0},
+		{FrameDirectionRequest, false, OpCachePut},
+		{FrameDirectionResponse, false, OpCachePut},
+		{FrameDirectionRequest, false, OpCacheGet},
+		{FrameDirectionResponse, false, OpCacheGet},
+	}
+	if len(frames) != len(want) {
+		t.Fatalf("recorded %d frames, want %d", len(frames), len(want))
+	}
+	for i, w := range want {
+		f := frames[i]
+		if f.Direction != w.direction || f.Handshake != w.handshake || f.OpCode != w.op || f.Conn != frames[0].Conn {
+			t.Errorf("frame %d = %+v, want %+v", i, f, w)
+		}
+		if i > 0 && !w.handshake && f.UID != frames[i-i%2].UID {
+			t.Errorf("frame %d UID = %d, want UID of the request %d", i, f.UID, frames[i-i%2].UID)
+		}
+	}
+
+	// replay
+	rp, err := NewReplayer(bytes.NewReader(recording.Bytes()))
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+	c, err = Connect(ConnInfo{Network: "tcp", Host: "::1", Port: 10800, Major: 1, Minor: 7, Patch: 0, Dial: rp.Dial})
+	if err != nil {
+		t.Fatalf("Connect() to replayer error = %v", err)
+	}
+	defer c.Close()
+	if !c.FeatureSupported(FeatureHeartbeat) {
+		t.Errorf("FeatureSupported(FeatureHeartbeat) = false after replayed handshake")
+	}
+	if err = c.CachePut("TestCache", false, "key", "value"); err != nil {
+		t.Fatalf("replayed CachePut() error = %v", err)
+	}
+	// request differs from recorded one
+	if _, err = c.CacheGet("TestCache", false, "other key"); err == nil {
+		t.Errorf("replayed CacheGet() error = nil for different request")
+	}
+	if rp.Err() == nil {
+		t.Errorf("Replayer.Err() = nil for different request")
+	}
+	if _, err = rp.Dial("tcp", ""); err == nil {
+		t.Errorf("Replayer.Dial() error = nil when there are no more recorded connections")
+	}
+}
+
+func Test_readFrame(t *testing.T) {
+	tests := []struct {
+		name    string
+		length  int32
+		want    []byte
+		wantErr bool
+	}{
+		{name: "1", length: 3, want: []byte{1, 2, 3}},
+		{name: "2", length: -1, wantErr: true},
+		{name: "3", length: maxFrameLength + 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &bytes.Buffer{}
+			WriteInt(r, tt.length)
+			r.Write([]byte{1, 2, 3})
+			got, err := readFrame(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readFrame() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, errors.ErrDecode) {
+				t.Errorf("readFrame() error = %v, want decode error", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("readFrame() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
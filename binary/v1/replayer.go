@@ -0,0 +1,157 @@
+package ignite
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+// Replayer plays traffic recorded by TrafficRecorder back to the client as fake server.
+// Use Replayer.Dial as ConnInfo.Dial function to connect to it.
+//
+// Requests of the client are compared with recorded requests ignoring request UIDs,
+// recorded responses are sent with UIDs of the actual requests.
+type Replayer struct {
+	mutex sync.Mutex
+	// conns is frames of the recorded connections in order of the first frame
+	conns [][]RecordedFrame
+	next  int
+	err   error
+}
+
+// NewReplayer reads recording created by TrafficRecorder
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	rp := &Replayer{}
+	index := map[int64]int{}
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for i := 0; ; i++ {
+		var f RecordedFrame
+		if err := dec.Decode(&f); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrapf(err, "failed to read recorded frame with index %d", i)
+		}
+		n, ok := index[f.Conn]
+		if !ok {
+			n = len(rp.conns)
+			index[f.Conn] = n
+			rp.conns = append(rp.conns, nil)
+		}
+		rp.conns[n] = append(rp.conns[n], f)
+	}
+
+	return rp, nil
+}
+
+// Dial returns connection which plays the next recorded connection.
+// Signature is the same as ConnInfo.Dial.
+func (rp *Replayer) Dial(network, address string) (net.Conn, error) {
+	rp.mutex.Lock()
+	defer rp.mutex.Unlock()
+
+	if rp.next >= len(rp.conns) {
+		return nil, errors.Errorf("there are no more recorded connections to replay (recorded %d)", len(rp.conns))
+	}
+	frames := rp.conns[rp.next]
+	rp.next++
+
+	cc, sc := net.Pipe()
+	go rp.serve(sc, frames)
+	return cc, nil
+}
+
+// Err returns the first difference between actual and recorded requests
+func (rp *Replayer) Err() error {
+	rp.mutex.Lock()
+	defer rp.mutex.Unlock()
+	return rp.err
+}
+
+func (rp *Replayer) setErr(err error) {
+	rp.mutex.Lock()
+	defer rp.mutex.Unlock()
+	if rp.err == nil {
+		rp.err = err
+	}
+}
+
+// serve plays frames of the recorded connection
+func (rp *Replayer) serve(conn net.Conn, frames []RecordedFrame) {
+	defer conn.Close()
+
+	// uids maps recorded request UIDs to actual ones
+	uids := map[int64]int64{}
+	for i, f := range frames {
+		switch f.Direction {
+		case FrameDirectionRequest:
+			data, err := readFrame(conn)
+			if err != nil {
+				if errors.Is(err, errors.ErrDecode) {
+					rp.setErr(errors.Wrapf(err, "failed to read request with index %d of the connection %d", i, f.Conn))
+				}
+				// otherwise client closed connection before the end of recording
+				return
+			}
+			if f.Handshake || len(f.Data) < 10 {
+				if !bytes.Equal(data, f.Data) {
+					rp.setErr(errors.Errorf("handshake request of the connection %d differs from recorded", f.Conn))
+					return
+				}
+				continue
+			}
+			if len(data) < 10 || !bytes.Equal(data[:2], f.Data[:2]) || !bytes.Equal(data[10:], f.Data[10:]) {
+				rp.setErr(errors.Errorf("request with index %d of the connection %d differs from recorded (op code %d)",
+					i, f.Conn, f.OpCode))
+				return
+			}
+			uids[f.UID] = int64(binary.LittleEndian.Uint64(data[2:]))
+
+		case FrameDirectionResponse:
+			data := append([]byte{}, f.Data...)
+			if !f.Handshake && len(data) >= 8 {
+				if uid, ok := uids[f.UID]; ok {
+					binary.LittleEndian.PutUint64(data, uint64(uid))
+				}
+			}
+			if err := WriteInt(conn, int32(len(data))); err != nil {
+				return
+			}
+			if _, err := conn.Write(data); err != nil {
+				return
+			}
+
+		default:
+			rp.setErr(errors.Errorf("invalid direction \"%s\" of the frame with index %d", f.Direction, i))
+			return
+		}
+	}
+
+	if _, err := readFrame(conn); err == nil {
+		rp.setErr(errors.Errorf("unexpected request after the end of recorded connection %d", frames[0].Conn))
+	}
+}
+
+// maxFrameLength is limit of the frame length read by replayer
+const maxFrameLength = 256 << 20
+
+// readFrame reads length prefixed frame
+func readFrame(r io.Reader) ([]byte, error) {
+	l, err := ReadInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if l < 0 || l > maxFrameLength {
+		return nil, errors.Decodef("invalid frame length %d (expected from 0 to %d)", l, maxFrameLength)
+	}
+	b := make([]byte, int(l))
+	_, err = io.ReadFull(r, b)
+	return b, err
+}
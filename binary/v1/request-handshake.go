@@ -33,6 +33,11 @@ func (r *RequestHandshake) WriteTo(w io.Writer) (int64, error) {
 	if err := WriteByte(r, 2); err != nil {
 		return 0, errors.Wrapf(err, "failed to write handshake client code")
 	}
+	if r.version().AtLeast(ProtocolVersion170) {
+		if err := WriteOArrayBytes(r, clientFeatures); err != nil {
+			return 0, errors.Wrapf(err, "failed to write handshake features")
+		}
+	}
 	if err := WriteOString(r, r.username); err != nil {
 		return 0, errors.Wrapf(err, "failed to write handshake username")
 	}
@@ -50,6 +55,11 @@ func (r *RequestHandshake) WriteTo(w io.Writer) (int64, error) {
 	return 4 + n, err
 }
 
+// version returns requested protocol version
+func (r *RequestHandshake) version() ProtocolVersion {
+	return ProtocolVersion{Major: r.major, Minor: r.minor, Patch: r.patch}
+}
+
 // NewRequestHandshake creates new handshake request object
 func NewRequestHandshake(major, minor, patch int, username, password string) *RequestHandshake {
 	return &RequestHandshake{request: newRequest(),
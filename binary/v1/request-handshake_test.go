@@ -22,6 +22,15 @@ func TestRequestHandshake_WriteTo(t *testing.T) {
 				0x9, 0x6, 0x0, 0x0, 0x0, 0x69, 0x67, 0x6e, 0x69, 0x74, 0x65, 0x9, 0x6,
 				0x0, 0x0, 0x0, 0x69, 0x67, 0x6e, 0x69, 0x74, 0x65},
 		},
+		{
+			name: "2",
+			r:    NewRequestHandshake(1, 7, 0, "", ""),
+			want: int64(4 + 8 + (1 + 4 + len(clientFeatures)) + 10),
+			wantW: append(append([]byte{byte(8 + 1 + 4 + len(clientFeatures) + 10), 0x0, 0x0, 0x0,
+				0x1, 0x1, 0x0, 0x7, 0x0, 0x0, 0x0, 0x2,
+				0xc, byte(len(clientFeatures)), 0x0, 0x0, 0x0}, clientFeatures...),
+				0x9, 0x0, 0x0, 0x0, 0x0, 0x9, 0x0, 0x0, 0x0, 0x0),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
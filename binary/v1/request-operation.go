@@ -40,6 +40,11 @@ func (r *RequestOperation) WriteTo(w io.Writer) (int64, error) {
 	return 4 + 2 + 8 + n, err
 }
 
+// operation returns the request, so interceptors can get operation code and UID of any request based on RequestOperation
+func (r *RequestOperation) operation() *RequestOperation {
+	return r
+}
+
 // NewRequestOperation creates new handshake request object
 func NewRequestOperation(code int16) *RequestOperation {
 	return &RequestOperation{request: newRequest(), Code: code, UID: rand.Int63()}
@@ -21,9 +21,14 @@ type request struct {
 }
 
 // WriteTo is function to write request data to io.Writer.
+// Payload is not consumed, so request can be sent again (e.g. by retry policy).
 // Returns written bytes.
 func (r *request) WriteTo(w io.Writer) (int64, error) {
-	return r.payload.WriteTo(w)
+	if r.payload.Len() == 0 {
+		return 0, nil
+	}
+	n, err := w.Write(r.payload.Bytes())
+	return int64(n), err
 }
 
 // Write writes len(p) bytes from p to the underlying data stream.
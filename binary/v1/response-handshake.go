@@ -1,8 +1,11 @@
 package ignite
 
 import (
+	"bytes"
 	"io"
 
+	"github.com/google/uuid"
+
 	"github.com/amsokol/ignite-go-client/binary/errors"
 )
 
@@ -14,6 +17,15 @@ type ResponseHandshake struct {
 	Major, Minor, Patch int
 	// Error message
 	Message string
+	// Error code (e.g. 2000 if authentication failed), zero if server did not send it
+	Status int32
+	// Features supported by server (protocol v1.7.0+)
+	Features []byte
+	// Server node ID (protocol v1.4.0+)
+	NodeID uuid.UUID
+
+	// requested protocol version
+	version ProtocolVersion
 
 	response
 }
@@ -29,33 +41,67 @@ func (r *ResponseHandshake) ReadFrom(rr io.Reader) (int64, error) {
 
 	r.Success, err = ReadBool(r)
 	if err != nil {
-		return 0, errors.Wrapf(err, "failed to read success flag")
+		return 0, errors.WrapDecodef(err, "failed to read success flag")
 	}
 
-	if !r.Success {
+	if r.Success {
+		if r.version.AtLeast(ProtocolVersion170) {
+			t, err := ReadByte(r)
+			if err != nil {
+				return 0, errors.WrapDecodef(err, "failed to read features type")
+			}
+			if t != typeByteArray {
+				return 0, errors.Decodef("invalid features type (expected %d, but got %d)", typeByteArray, t)
+			}
+			if r.Features, err = ReadArrayBytes(r); err != nil {
+				return 0, errors.WrapDecodef(err, "failed to read features")
+			}
+		}
+		if r.version.AtLeast(ProtocolVersion140) {
+			o, err := ReadObject(r)
+			if err != nil {
+				return 0, errors.WrapDecodef(err, "failed to read server node ID")
+			}
+			if id, ok := o.(uuid.UUID); ok {
+				r.NodeID = id
+			}
+		}
+	} else {
 		v, err := ReadShort(r)
 		if err != nil {
-			return 0, errors.Wrapf(err, "failed to read server version major")
+			return 0, errors.WrapDecodef(err, "failed to read server version major")
 		}
 		r.Major = int(v)
 
 		v, err = ReadShort(r)
 		if err != nil {
-			return 0, errors.Wrapf(err, "failed to read server version minor")
+			return 0, errors.WrapDecodef(err, "failed to read server version minor")
 		}
 		r.Minor = int(v)
 
 		v, err = ReadShort(r)
 		if err != nil {
-			return 0, errors.Wrapf(err, "failed to read server version patch")
+			return 0, errors.WrapDecodef(err, "failed to read server version patch")
 		}
 		r.Patch = int(v)
 
 		r.Message, err = ReadOString(r)
 		if err != nil {
-			return 0, errors.Wrapf(err, "failed to read error message")
+			return 0, errors.WrapDecodef(err, "failed to read error message")
+		}
+
+		// error code is sent by servers supporting authentication
+		if m, ok := r.message.(*bytes.Reader); ok && m.Len() >= 4 {
+			if r.Status, err = ReadInt(r); err != nil {
+				return 0, errors.WrapDecodef(err, "failed to read error code")
+			}
 		}
 	}
 
 	return n, nil
 }
+
+// NewResponseHandshake creates new handshake response object for requested protocol version
+func NewResponseHandshake(major, minor, patch int) *ResponseHandshake {
+	return &ResponseHandshake{version: ProtocolVersion{Major: major, Minor: minor, Patch: patch}}
+}
@@ -3,6 +3,7 @@ package ignite
 import (
 	"bytes"
 	"io"
+	"reflect"
 	"testing"
 )
 
@@ -13,8 +14,20 @@ func TestResponseHandshake_ReadFrom(t *testing.T) {
 		[]byte{23, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0,
 			9, 0x0B, 0, 0, 0, 0x74, 0x65, 0x73, 0x74, 0x20, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67})
 
+	rr3 := bytes.NewBuffer(
+		[]byte{25, 0, 0, 0, 1,
+			12, 2, 0, 0, 0, 0x10, 0x08,
+			10, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2})
+
+	rr4 := bytes.NewBuffer(
+		[]byte{27, 0, 0, 0, 0, 1, 0, 2, 0, 0, 0,
+			9, 0x0B, 0, 0, 0, 0x74, 0x65, 0x73, 0x74, 0x20, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67,
+			0xD0, 0x07, 0, 0})
+
 	r1 := &ResponseHandshake{}
 	r2 := &ResponseHandshake{}
+	r3 := NewResponseHandshake(1, 7, 0)
+	r4 := &ResponseHandshake{}
 
 	type args struct {
 		rr io.Reader
@@ -27,6 +40,8 @@ func TestResponseHandshake_ReadFrom(t *testing.T) {
 		wantSuccess                     bool
 		wantMajor, wantMinor, wantPatch int
 		wantMessage                     string
+		wantStatus                      int32
+		wantFeatures                    []byte
 		wantErr                         bool
 	}{
 		{
@@ -51,6 +66,30 @@ func TestResponseHandshake_ReadFrom(t *testing.T) {
 			wantPatch:   0,
 			wantMessage: "test string",
 		},
+		{
+			name: "3",
+			r:    r3,
+			args: args{
+				rr: rr3,
+			},
+			want:         4 + 25,
+			wantSuccess:  true,
+			wantFeatures: []byte{0x10, 0x08},
+		},
+		{
+			name: "4",
+			r:    r4,
+			args: args{
+				rr: rr4,
+			},
+			want:        4 + 27,
+			wantSuccess: false,
+			wantMajor:   1,
+			wantMinor:   2,
+			wantPatch:   0,
+			wantMessage: "test string",
+			wantStatus:  2000,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -77,6 +116,12 @@ func TestResponseHandshake_ReadFrom(t *testing.T) {
 			if tt.r.Message != tt.wantMessage {
 				t.Errorf("ResponseHandshake.ReadFrom() message = %v, want %v", tt.r.Message, tt.wantMessage)
 			}
+			if tt.r.Status != tt.wantStatus {
+				t.Errorf("ResponseHandshake.ReadFrom() status = %v, want %v", tt.r.Status, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(tt.r.Features, tt.wantFeatures) {
+				t.Errorf("ResponseHandshake.ReadFrom() features = %v, want %v", tt.r.Features, tt.wantFeatures)
+			}
 		})
 	}
 }
@@ -11,6 +11,13 @@ const (
 	OperationStatusSuccess = 0
 )
 
+const (
+	// ResponseFlagError means operation failed (protocol v1.4.0+)
+	ResponseFlagError = 1
+	// ResponseFlagAffinityTopologyChanged means affinity topology version is present (protocol v1.4.0+)
+	ResponseFlagAffinityTopologyChanged = 2
+)
+
 // ResponseOperation is struct operation response
 type ResponseOperation struct {
 	// Request id
@@ -19,6 +26,14 @@ type ResponseOperation struct {
 	Status int32
 	// Error message (present only when status is not 0)
 	Message string
+	// Response flags (protocol v1.4.0+)
+	Flags int16
+	// Affinity topology version (present only when ResponseFlagAffinityTopologyChanged flag is set)
+	TopologyVersion      int64
+	TopologyMinorVersion int32
+
+	// negotiated protocol version
+	version ProtocolVersion
 
 	response
 }
@@ -34,23 +49,43 @@ func (r *ResponseOperation) ReadFrom(rr io.Reader) (int64, error) {
 
 	uid, err := ReadLong(r)
 	if err != nil {
-		return 0, errors.Wrapf(err, "failed to read operation request id")
+		return 0, errors.WrapDecodef(err, "failed to read operation request id")
 	}
 
-	r.Status, err = ReadInt(r)
-	if err != nil {
-		return 0, errors.Wrapf(err, "failed to read status code")
+	if r.version.AtLeast(ProtocolVersion140) {
+		if r.Flags, err = ReadShort(r); err != nil {
+			return 0, errors.WrapDecodef(err, "failed to read flags")
+		}
+		if r.Flags&ResponseFlagAffinityTopologyChanged != 0 {
+			if r.TopologyVersion, err = ReadLong(r); err != nil {
+				return 0, errors.WrapDecodef(err, "failed to read affinity topology version")
+			}
+			if r.TopologyMinorVersion, err = ReadInt(r); err != nil {
+				return 0, errors.WrapDecodef(err, "failed to read affinity topology minor version")
+			}
+		}
+		if r.Flags&ResponseFlagError != 0 {
+			if r.Status, err = ReadInt(r); err != nil {
+				return 0, errors.WrapDecodef(err, "failed to read status code")
+			}
+		} else {
+			r.Status = OperationStatusSuccess
+		}
+	} else {
+		if r.Status, err = ReadInt(r); err != nil {
+			return 0, errors.WrapDecodef(err, "failed to read status code")
+		}
 	}
 
 	if r.Status != OperationStatusSuccess {
 		r.Message, err = ReadOString(r)
 		if err != nil {
-			return 0, errors.Wrapf(err, "failed to read error message")
+			return 0, errors.WrapDecodef(err, "failed to read error message")
 		}
 	}
 
 	if uid != r.UID {
-		return n, errors.Errorf("invalid request ID: got %d, but expected %d", uid, r.UID)
+		return n, errors.Decodef("invalid request ID: got %d, but expected %d", uid, r.UID)
 	}
 
 	return n, nil
@@ -67,6 +102,11 @@ func (r *ResponseOperation) CheckStatus() error {
 	return nil
 }
 
+// setProtocolVersion sets negotiated protocol version to read response header properly
+func (r *ResponseOperation) setProtocolVersion(v ProtocolVersion) {
+	r.version = v
+}
+
 // NewResponseOperation is ResponseOperation constructor
 func NewResponseOperation(uid int64) *ResponseOperation {
 	return &ResponseOperation{UID: uid}
@@ -18,9 +18,23 @@ func TestResponseOperation_ReadFrom(t *testing.T) {
 		[]byte{12, 0, 0, 0,
 			3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
 
+	rr4 := bytes.NewBuffer(
+		[]byte{42, 0, 0, 0,
+			4, 0, 0, 0, 0, 0, 0, 0, 3, 0,
+			5, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0,
+			2, 0, 0, 0,
+			9, 0x0B, 0, 0, 0, 0x74, 0x65, 0x73, 0x74, 0x20, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67})
+	rr5 := bytes.NewBuffer(
+		[]byte{10, 0, 0, 0,
+			5, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+
 	r1 := NewResponseOperation(1)
 	r2 := NewResponseOperation(2)
 	r3 := NewResponseOperation(0)
+	r4 := NewResponseOperation(4)
+	r4.setProtocolVersion(ProtocolVersion170)
+	r5 := NewResponseOperation(5)
+	r5.setProtocolVersion(ProtocolVersion140)
 
 	type args struct {
 		rr io.Reader
@@ -65,6 +79,26 @@ func TestResponseOperation_ReadFrom(t *testing.T) {
 			want:    4 + 12,
 			wantErr: true,
 		},
+		{
+			name: "4",
+			r:    r4,
+			args: args{
+				rr: rr4,
+			},
+			want:        4 + 42,
+			wantUID:     4,
+			wantStatus:  2,
+			wantMessage: "test string",
+		},
+		{
+			name: "5",
+			r:    r5,
+			args: args{
+				rr: rr5,
+			},
+			want:    4 + 10,
+			wantUID: 5,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,81 @@
+package ignite
+
+import (
+	"database/sql/driver"
+	"time"
+	"unicode/utf8"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+// Value implements driver.Valuer, Date is converted to time.Time in UTC
+func (d Date) Value() (driver.Value, error) {
+	return time.Unix(int64(d)/1000, (int64(d)%1000)*int64(time.Millisecond)).UTC(), nil
+}
+
+// Scan implements sql.Scanner, src must be time.Time or milliseconds since January 1, 1970 UTC (int64)
+func (d *Date) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case time.Time:
+		*d = ToDate(v)
+	case int64:
+		*d = Date(v)
+	case Date:
+		*d = v
+	default:
+		return errors.Errorf("failed to scan %T into ignite.Date", src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, Time is converted to time.Time of January 1, 1 UTC
+// (the same value is returned by SQL driver for TIME columns)
+func (t Time) Value() (driver.Value, error) {
+	v := time.Unix(int64(t)/1000, (int64(t)%1000)*int64(time.Millisecond)).UTC()
+	return time.Date(1, 1, 1, v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), time.UTC), nil
+}
+
+// Scan implements sql.Scanner, src must be time.Time (date is ignored) or milliseconds since midnight (int64)
+func (t *Time) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case time.Time:
+		*t = ToTime(v)
+	case int64:
+		*t = Time(v)
+	case Time:
+		*t = v
+	default:
+		return errors.Errorf("failed to scan %T into ignite.Time", src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, Char is converted to string
+func (c Char) Value() (driver.Value, error) {
+	return string(rune(c)), nil
+}
+
+// Scan implements sql.Scanner, src must be one character string or []byte or code point (int64)
+func (c *Char) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		*c = Char(v)
+		return nil
+	case Char:
+		*c = v
+		return nil
+	default:
+		return errors.Errorf("failed to scan %T into ignite.Char", src)
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 || size != len(s) || r == utf8.RuneError {
+		return errors.Errorf("failed to scan \"%s\" into ignite.Char: one character expected", s)
+	}
+	*c = Char(r)
+	return nil
+}
@@ -0,0 +1,100 @@
+package ignite
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDate_Value(t *testing.T) {
+	tm := time.Date(2018, 4, 3, 14, 25, 32, int(123*time.Millisecond), time.UTC)
+	got, err := ToDate(tm).Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, tm) {
+		t.Errorf("Value() = %v, want %v", got, tm)
+	}
+}
+
+func TestDate_Scan(t *testing.T) {
+	tm := time.Date(2018, 4, 3, 14, 25, 32, int(123*time.Millisecond), time.UTC)
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    Date
+		wantErr bool
+	}{
+		{name: "time", src: tm, want: ToDate(tm)},
+		{name: "milliseconds", src: int64(1522765532123), want: ToDate(tm)},
+		{name: "string", src: "2018-04-03", wantErr: true},
+		{name: "null", src: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Date
+			if err := d.Scan(tt.src); (err != nil) != tt.wantErr {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if d != tt.want {
+				t.Errorf("Scan() = %v, want %v", d, tt.want)
+			}
+		})
+	}
+}
+
+func TestTime_Value_Scan(t *testing.T) {
+	tm := time.Date(2018, 4, 3, 14, 25, 32, int(123*time.Millisecond), time.UTC)
+	v, err := ToTime(tm).Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	want := time.Date(1, 1, 1, 14, 25, 32, int(123*time.Millisecond), time.UTC)
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("Value() = %v, want %v", v, want)
+	}
+
+	var got Time
+	if err = got.Scan(v); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if got != ToTime(tm) {
+		t.Errorf("Scan() = %v, want %v", got, ToTime(tm))
+	}
+	if err = got.Scan("14:25:32"); err == nil {
+		t.Errorf("Scan() of string error = nil")
+	}
+}
+
+func TestChar_Value_Scan(t *testing.T) {
+	if v, err := Char('Ж').Value(); err != nil || v != driver.Value("Ж") {
+		t.Errorf("Value() = %v, %v, want \"Ж\"", v, err)
+	}
+
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    Char
+		wantErr bool
+	}{
+		{name: "string", src: "Ж", want: 'Ж'},
+		{name: "bytes", src: []byte("a"), want: 'a'},
+		{name: "code point", src: int64('a'), want: 'a'},
+		{name: "char", src: Char('a'), want: 'a'},
+		{name: "empty string", src: "", wantErr: true},
+		{name: "long string", src: "ab", wantErr: true},
+		{name: "float", src: 1.5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Char
+			if err := c.Scan(tt.src); (err != nil) != tt.wantErr {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if c != tt.want {
+				t.Errorf("Scan() = %v, want %v", c, tt.want)
+			}
+		})
+	}
+}
@@ -37,7 +37,7 @@ const (
 	typeUUIDArray   = 21
 	typeDateArray   = 22
 	// TODO: Object array = 23
-	// TODO: Collection = 24
+	typeCollection = 24
 	// TODO: Map = 25
 	typeBinaryObjectArray = 27
 	// TODO: Enum = 28
@@ -52,6 +52,46 @@ const (
 	typeComplexObject  = 103
 )
 
+// sqlTypeNames maps type codes to Apache Ignite SQL type names
+var sqlTypeNames = map[byte]string{
+	typeByte:              "TINYINT",
+	typeShort:             "SMALLINT",
+	typeInt:               "INTEGER",
+	typeLong:              "BIGINT",
+	typeFloat:             "REAL",
+	typeDouble:            "DOUBLE",
+	typeChar:              "CHAR",
+	typeBool:              "BOOLEAN",
+	typeString:            "VARCHAR",
+	typeUUID:              "UUID",
+	typeDate:              "DATE",
+	typeByteArray:         "BINARY",
+	typeShortArray:        "ARRAY",
+	typeIntArray:          "ARRAY",
+	typeLongArray:         "ARRAY",
+	typeFloatArray:        "ARRAY",
+	typeDoubleArray:       "ARRAY",
+	typeCharArray:         "ARRAY",
+	typeBoolArray:         "ARRAY",
+	typeStringArray:       "ARRAY",
+	typeUUIDArray:         "ARRAY",
+	typeDateArray:         "ARRAY",
+	typeCollection:        "ARRAY",
+	typeBinaryObjectArray: "ARRAY",
+	typeTimestamp:         "TIMESTAMP",
+	typeTimestampArray:    "ARRAY",
+	typeTime:              "TIME",
+	typeTimeArray:         "ARRAY",
+	typeComplexObject:     "OTHER",
+}
+
+// SQLTypeName returns Apache Ignite SQL type name of the value with the type code, e.g. "VARCHAR", "BIGINT".
+// Type name of the collections and arrays is "ARRAY".
+// Returns empty string for null and unsupported type codes.
+func SQLTypeName(typeCode byte) string {
+	return sqlTypeNames[typeCode]
+}
+
 const (
 	// ComplexObjectHeaderLength is complex object header length
 	ComplexObjectHeaderLength = 24
@@ -266,6 +306,12 @@ func WriteOUUID(w io.Writer, v uuid.UUID) error {
 	return binary.Write(w, binary.LittleEndian, v)
 }
 
+// WriteUUID writes "UUID" value
+func WriteUUID(w io.Writer, v uuid.UUID) error {
+	uuidFlip(&v)
+	return binary.Write(w, binary.LittleEndian, v)
+}
+
 // WriteODate writes "Date" object value
 func WriteODate(w io.Writer, v Date) error {
 	if err := WriteType(w, typeDate); err != nil {
@@ -704,7 +750,7 @@ func ReadBool(r io.Reader) (bool, error) {
 	case 0:
 		return false, nil
 	default:
-		return false, errors.Errorf("invalid bool value: %d", v)
+		return false, errors.Decodef("invalid bool value: %d", v)
 	}
 }
 
@@ -737,7 +783,7 @@ func ReadOString(r io.Reader) (string, error) {
 		v, err := ReadString(r)
 		return v, err
 	default:
-		return "", errors.Errorf("invalid type (expected %d, but got %d)", typeString, t)
+		return "", errors.Decodef("invalid type (expected %d, but got %d)", typeString, t)
 	}
 }
 
@@ -760,7 +806,7 @@ func ReadDate(r io.Reader) (time.Time, error) {
 
 // ReadArrayBytes reads "byte" array value
 func ReadArrayBytes(r io.Reader) ([]byte, error) {
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -773,7 +819,7 @@ func ReadArrayBytes(r io.Reader) ([]byte, error) {
 
 // ReadArrayShorts reads "short" array value
 func ReadArrayShorts(r io.Reader) ([]int16, error) {
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -786,7 +832,7 @@ func ReadArrayShorts(r io.Reader) ([]int16, error) {
 
 // ReadArrayInts reads "int" array value
 func ReadArrayInts(r io.Reader) ([]int32, error) {
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -799,7 +845,7 @@ func ReadArrayInts(r io.Reader) ([]int32, error) {
 
 // ReadArrayLongs reads "long" array value
 func ReadArrayLongs(r io.Reader) ([]int64, error) {
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -812,7 +858,7 @@ func ReadArrayLongs(r io.Reader) ([]int64, error) {
 
 // ReadArrayFloats reads "float" array value
 func ReadArrayFloats(r io.Reader) ([]float32, error) {
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -825,7 +871,7 @@ func ReadArrayFloats(r io.Reader) ([]float32, error) {
 
 // ReadArrayDoubles reads "double" array value
 func ReadArrayDoubles(r io.Reader) ([]float64, error) {
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -838,7 +884,7 @@ func ReadArrayDoubles(r io.Reader) ([]float64, error) {
 
 // ReadArrayChars reads "char" array value
 func ReadArrayChars(r io.Reader) ([]Char, error) {
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -853,7 +899,7 @@ func ReadArrayChars(r io.Reader) ([]Char, error) {
 
 // ReadArrayBools reads "bool" array value
 func ReadArrayBools(r io.Reader) ([]bool, error) {
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -866,7 +912,7 @@ func ReadArrayBools(r io.Reader) ([]bool, error) {
 
 // ReadArrayOStrings reads "String" array value
 func ReadArrayOStrings(r io.Reader) ([]string, error) {
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -881,7 +927,7 @@ func ReadArrayOStrings(r io.Reader) ([]string, error) {
 
 // ReadArrayOUUIDs reads "UUID" array value
 func ReadArrayOUUIDs(r io.Reader) ([]uuid.UUID, error) {
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -898,7 +944,7 @@ func ReadArrayOUUIDs(r io.Reader) ([]uuid.UUID, error) {
 
 // ReadArrayODates reads "Date" array value
 func ReadArrayODates(r io.Reader) ([]time.Time, error) {
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -916,7 +962,7 @@ func ReadArrayODates(r io.Reader) ([]time.Time, error) {
 // ReadArrayBinaryObject reads "binary object" value wrapped by array
 func ReadArrayBinaryObject(r io.Reader) (interface{}, error) {
 	// read byte array size
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -933,11 +979,46 @@ func ReadArrayBinaryObject(r io.Reader) (interface{}, error) {
 		return nil, err
 	}
 
+	if o < 0 || int(o) > len(b) {
+		return nil, errors.Decodef("invalid binary object offset %d (array length is %d)", o, len(b))
+	}
+
 	// read object
 	buf := bytes.NewBuffer(b[int(o):])
 	return ReadObject(buf)
 }
 
+// readLength reads length of the array or collection, negative length is decode error
+func readLength(r io.Reader) (int32, error) {
+	l, err := ReadInt(r)
+	if err != nil {
+		return 0, err
+	}
+	if l < 0 {
+		return 0, errors.Decodef("invalid length %d (expected non-negative value)", l)
+	}
+	return l, nil
+}
+
+// ReadCollection reads "collection" value
+func ReadCollection(r io.Reader) ([]interface{}, error) {
+	l, err := readLength(r)
+	if err != nil {
+		return nil, err
+	}
+	// collection type is not used
+	if _, err = ReadByte(r); err != nil {
+		return nil, err
+	}
+	b := make([]interface{}, l)
+	for i := 0; i < int(l); i++ {
+		if b[i], err = ReadObject(r); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
 // ReadTimestamp reads "Timestamp" object value
 func ReadTimestamp(r io.Reader) (time.Time, error) {
 	high, err := ReadLong(r)
@@ -955,7 +1036,7 @@ func ReadTimestamp(r io.Reader) (time.Time, error) {
 
 // ReadArrayOTimestamps reads "Timestamp" array value
 func ReadArrayOTimestamps(r io.Reader) ([]time.Time, error) {
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -982,7 +1063,7 @@ func ReadTime(r io.Reader) (time.Time, error) {
 
 // ReadArrayOTimes reads "Time" array value
 func ReadArrayOTimes(r io.Reader) ([]time.Time, error) {
-	l, err := ReadInt(r)
+	l, err := readLength(r)
 	if err != nil {
 		return nil, err
 	}
@@ -1005,7 +1086,7 @@ func ReadComplexObject(r io.Reader) (ComplexObject, error) {
 		return ComplexObject{}, err
 	}
 	if ver != ComplexObjectVersion {
-		return ComplexObject{}, errors.Errorf("invalid complex object version %d, but expected %d", ver, ComplexObjectVersion)
+		return ComplexObject{}, errors.Decodef("invalid complex object version %d, but expected %d", ver, ComplexObjectVersion)
 	}
 
 	// read flags
@@ -1112,11 +1193,22 @@ func ReadComplexObject(r io.Reader) (ComplexObject, error) {
 
 // ReadObject read object
 func ReadObject(r io.Reader) (interface{}, error) {
+	_, o, err := ReadObjectWithType(r)
+	return o, err
+}
+
+// ReadObjectWithType reads object and returns its type code too
+func ReadObjectWithType(r io.Reader) (byte, interface{}, error) {
 	t, err := ReadByte(r)
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
+	o, err := readObjectValue(r, t)
+	return t, o, err
+}
 
+// readObjectValue reads object value with type code t
+func readObjectValue(r io.Reader, t byte) (interface{}, error) {
 	switch t {
 	case typeByte:
 		return ReadByte(r)
@@ -1160,6 +1252,8 @@ func ReadObject(r io.Reader) (interface{}, error) {
 		return ReadArrayOStrings(r)
 	case typeDateArray:
 		return ReadArrayODates(r)
+	case typeCollection:
+		return ReadCollection(r)
 	case typeBinaryObjectArray:
 		return ReadArrayBinaryObject(r)
 	case typeUUIDArray:
@@ -1177,6 +1271,6 @@ func ReadObject(r io.Reader) (interface{}, error) {
 	case typeComplexObject:
 		return ReadComplexObject(r)
 	default:
-		return nil, errors.Errorf("unsupported object type: %d", t)
+		return nil, errors.Decodef("unsupported object type: %d", t)
 	}
 }
@@ -2681,3 +2681,55 @@ func Test_response_ReadObject(t *testing.T) {
 		})
 	}
 }
+
+func TestReadObjectWithType(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		wantType byte
+		want     interface{}
+		wantErr  bool
+	}{
+		{name: "int", data: []byte{3, 1, 0, 0, 0}, wantType: typeInt, want: int32(1)},
+		{name: "string", data: []byte{9, 2, 0, 0, 0, 'o', 'k'}, wantType: typeString, want: "ok"},
+		{name: "null", data: []byte{101}, wantType: typeNULL},
+		{name: "unsupported", data: []byte{127}, wantType: 127, wantErr: true},
+		{name: "empty", wantErr: true},
+		{name: "collection", data: []byte{24, 1, 0, 0, 0, 1, 3, 7, 0, 0, 0}, wantType: typeCollection, want: []interface{}{int32(7)}},
+		{name: "negative collection length", data: []byte{24, 0xff, 0xff, 0xff, 0xff, 1}, wantType: typeCollection, wantErr: true},
+		{name: "negative array length", data: []byte{14, 0xfe, 0xff, 0xff, 0xff}, wantType: typeIntArray, wantErr: true},
+		{name: "invalid binary object offset", data: []byte{27, 1, 0, 0, 0, 101, 2, 0, 0, 0}, wantType: typeBinaryObjectArray, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, got, err := ReadObjectWithType(bytes.NewReader(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadObjectWithType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotType != tt.wantType || (err == nil && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("ReadObjectWithType() = %d, %v, want %d, %v", gotType, got, tt.wantType, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLTypeName(t *testing.T) {
+	tests := []struct {
+		typeCode byte
+		want     string
+	}{
+		{typeCode: typeLong, want: "BIGINT"},
+		{typeCode: typeString, want: "VARCHAR"},
+		{typeCode: typeTimestamp, want: "TIMESTAMP"},
+		{typeCode: typeStringArray, want: "ARRAY"},
+		{typeCode: typeCollection, want: "ARRAY"},
+		{typeCode: typeComplexObject, want: "OTHER"},
+		{typeCode: typeNULL, want: ""},
+		{typeCode: 30, want: ""},
+	}
+	for _, tt := range tests {
+		if got := SQLTypeName(tt.typeCode); got != tt.want {
+			t.Errorf("SQLTypeName(%d) = %q, want %q", tt.typeCode, got, tt.want)
+		}
+	}
+}
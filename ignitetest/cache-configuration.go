@@ -0,0 +1,428 @@
+package ignitetest
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/amsokol/ignite-go-client/binary/v1"
+)
+
+// codes of cache configuration properties
+const (
+	propName                          = 0
+	propCacheMode                     = 1
+	propAtomicityMode                 = 2
+	propBackups                       = 3
+	propWriteSynchronizationMode      = 4
+	propCopyOnRead                    = 5
+	propReadFromBackup                = 6
+	propDataRegionName                = 100
+	propOnheapCacheEnabled            = 101
+	propQueryEntities                 = 200
+	propQueryParallelism              = 201
+	propQueryDetailMetricsSize        = 202
+	propSQLSchema                     = 203
+	propSQLIndexInlineMaxSize         = 204
+	propSQLEscapeAll                  = 205
+	propMaxQueryIterators             = 206
+	propRebalanceMode                 = 300
+	propRebalanceDelay                = 301
+	propRebalanceTimeout              = 302
+	propRebalanceBatchSize            = 303
+	propRebalanceBatchesPrefetchCount = 304
+	propRebalanceOrder                = 305
+	propRebalanceThrottle             = 306
+	propGroupName                     = 400
+	propCacheKeyConfigurations        = 401
+	propLockTimeout                   = 402
+	propMaxConcurrentAsyncOperations  = 403
+	propPartitionLossPolicy           = 404
+	propEagerTTL                      = 405
+	propEnableStatistics              = 406
+	propExpiryPolicy                  = 407
+)
+
+// defaultCacheConfiguration returns configuration of the cache created by name
+func defaultCacheConfiguration(name string) ignite.CacheConfiguration {
+	return ignite.CacheConfiguration{
+		Name:                          name,
+		AtomicityMode:                 ignite.CacheAtomicityModeAtomic,
+		CacheMode:                     ignite.CacheModePartitioned,
+		CopyOnRead:                    true,
+		EagerTTL:                      true,
+		MaxConcurrentAsyncOperations:  500,
+		MaxQueryIterators:             1024,
+		PartitionLossPolicy:           ignite.PartitionLossPolicyIgnore,
+		QueryParellelism:              1,
+		ReadFromBackup:                true,
+		RebalanceBatchSize:            512 * 1024,
+		RebalanceBatchesPrefetchCount: 2,
+		RebalanceMode:                 1,
+		RebalanceTimeout:              10000,
+		SQLIndexInlineMaxSize:         -1,
+		WriteSynchronizationMode:      ignite.WriteSynchronizationModePrimarySync,
+	}
+}
+
+// readCacheConfiguration reads properties of OP_CACHE_CREATE_WITH_CONFIGURATION request
+func readCacheConfiguration(r io.Reader) (ignite.CacheConfiguration, error) {
+	cc := defaultCacheConfiguration("")
+
+	if _, err := ignite.ReadInt(r); err != nil {
+		return cc, err
+	}
+	count, err := ignite.ReadShort(r)
+	if err != nil {
+		return cc, err
+	}
+	for i := 0; i < int(count); i++ {
+		code, err := ignite.ReadShort(r)
+		if err != nil {
+			return cc, err
+		}
+		switch code {
+		case propName:
+			cc.Name, err = ignite.ReadOString(r)
+		case propCacheMode:
+			cc.CacheMode, err = ignite.ReadInt(r)
+		case propAtomicityMode:
+			cc.AtomicityMode, err = ignite.ReadInt(r)
+		case propBackups:
+			cc.Backups, err = ignite.ReadInt(r)
+		case propWriteSynchronizationMode:
+			cc.WriteSynchronizationMode, err = ignite.ReadInt(r)
+		case propCopyOnRead:
+			cc.CopyOnRead, err = ignite.ReadBool(r)
+		case propReadFromBackup:
+			cc.ReadFromBackup, err = ignite.ReadBool(r)
+		case propDataRegionName:
+			cc.DataRegionName, err = ignite.ReadOString(r)
+		case propOnheapCacheEnabled:
+			cc.OnheapCacheEnabled, err = ignite.ReadBool(r)
+		case propQueryEntities:
+			cc.QueryEntities, err = readQueryEntities(r)
+		case propQueryParallelism:
+			cc.QueryParellelism, err = ignite.ReadInt(r)
+		case propQueryDetailMetricsSize:
+			cc.QueryDetailMetricsSize, err = ignite.ReadInt(r)
+		case propSQLSchema:
+			cc.SQLSchema, err = ignite.ReadOString(r)
+		case propSQLIndexInlineMaxSize:
+			cc.SQLIndexInlineMaxSize, err = ignite.ReadInt(r)
+		case propSQLEscapeAll:
+			cc.SQLEscapeAll, err = ignite.ReadBool(r)
+		case propMaxQueryIterators:
+			cc.MaxQueryIterators, err = ignite.ReadInt(r)
+		case propRebalanceMode:
+			cc.RebalanceMode, err = ignite.ReadInt(r)
+		case propRebalanceDelay:
+			cc.RebalanceDelay, err = ignite.ReadLong(r)
+		case propRebalanceTimeout:
+			cc.RebalanceTimeout, err = ignite.ReadLong(r)
+		case propRebalanceBatchSize:
+			cc.RebalanceBatchSize, err = ignite.ReadInt(r)
+		case propRebalanceBatchesPrefetchCount:
+			cc.RebalanceBatchesPrefetchCount, err = ignite.ReadLong(r)
+		case propRebalanceOrder:
+			cc.RebalanceOrder, err = ignite.ReadInt(r)
+		case propRebalanceThrottle:
+			cc.RebalanceThrottle, err = ignite.ReadLong(r)
+		case propGroupName:
+			cc.GroupName, err = ignite.ReadOString(r)
+		case propCacheKeyConfigurations:
+			cc.CacheKeyConfigurations, err = readCacheKeyConfigurations(r)
+		case propLockTimeout:
+			cc.LockTimeout, err = ignite.ReadLong(r)
+		case propMaxConcurrentAsyncOperations:
+			cc.MaxConcurrentAsyncOperations, err = ignite.ReadInt(r)
+		case propPartitionLossPolicy:
+			cc.PartitionLossPolicy, err = ignite.ReadInt(r)
+		case propEagerTTL:
+			cc.EagerTTL, err = ignite.ReadBool(r)
+		case propEnableStatistics:
+			cc.EnableStatistics, err = ignite.ReadBool(r)
+		case propExpiryPolicy:
+			cc.ExpiryPolicy, err = readExpiryPolicy(r)
+		default:
+			return cc, newStatusError(statusFailed, "unknown cache configuration property with code %d", code)
+		}
+		if err != nil {
+			return cc, err
+		}
+	}
+	return cc, nil
+}
+
+// readExpiryPolicy reads expiry policy property
+func readExpiryPolicy(r io.Reader) (*ignite.ExpiryPolicy, error) {
+	ok, err := ignite.ReadBool(r)
+	if err != nil || !ok {
+		return nil, err
+	}
+	var ms [3]int64
+	for i := range ms {
+		if ms[i], err = ignite.ReadLong(r); err != nil {
+			return nil, err
+		}
+	}
+	return &ignite.ExpiryPolicy{Create: durationFromMillis(ms[0]), Update: durationFromMillis(ms[1]),
+		Access: durationFromMillis(ms[2])}, nil
+}
+
+func readCacheKeyConfigurations(r io.Reader) ([]ignite.CacheKeyConfiguration, error) {
+	count, err := ignite.ReadInt(r)
+	if err != nil {
+		return nil, err
+	}
+	ckcs := make([]ignite.CacheKeyConfiguration, 0, int(count))
+	for i := 0; i < int(count); i++ {
+		var ckc ignite.CacheKeyConfiguration
+		if ckc.TypeName, err = ignite.ReadOString(r); err != nil {
+			return nil, err
+		}
+		if ckc.AffinityKeyFieldName, err = ignite.ReadOString(r); err != nil {
+			return nil, err
+		}
+		ckcs = append(ckcs, ckc)
+	}
+	return ckcs, nil
+}
+
+func readQueryEntities(r io.Reader) ([]ignite.QueryEntity, error) {
+	count, err := ignite.ReadInt(r)
+	if err != nil {
+		return nil, err
+	}
+	qes := make([]ignite.QueryEntity, 0, int(count))
+	for i := 0; i < int(count); i++ {
+		var qe ignite.QueryEntity
+		for _, s := range []*string{&qe.KeyTypeName, &qe.ValueTypeName, &qe.TableName, &qe.KeyFieldName, &qe.ValueFieldName} {
+			if *s, err = ignite.ReadOString(r); err != nil {
+				return nil, err
+			}
+		}
+
+		n, err := ignite.ReadInt(r)
+		if err != nil {
+			return nil, err
+		}
+		for j := 0; j < int(n); j++ {
+			var qf ignite.QueryField
+			if qf.Name, err = ignite.ReadOString(r); err != nil {
+				return nil, err
+			}
+			if qf.TypeName, err = ignite.ReadOString(r); err != nil {
+				return nil, err
+			}
+			if qf.IsKeyField, err = ignite.ReadBool(r); err != nil {
+				return nil, err
+			}
+			if qf.IsNotNullConstraintField, err = ignite.ReadBool(r); err != nil {
+				return nil, err
+			}
+			qe.QueryFields = append(qe.QueryFields, qf)
+		}
+
+		if n, err = ignite.ReadInt(r); err != nil {
+			return nil, err
+		}
+		for j := 0; j < int(n); j++ {
+			var fna ignite.FieldNameAlias
+			if fna.Name, err = ignite.ReadOString(r); err != nil {
+				return nil, err
+			}
+			if fna.Alias, err = ignite.ReadOString(r); err != nil {
+				return nil, err
+			}
+			qe.FieldNameAliases = append(qe.FieldNameAliases, fna)
+		}
+
+		if n, err = ignite.ReadInt(r); err != nil {
+			return nil, err
+		}
+		for j := 0; j < int(n); j++ {
+			var qi ignite.QueryIndex
+			if qi.Name, err = ignite.ReadOString(r); err != nil {
+				return nil, err
+			}
+			if qi.Type, err = ignite.ReadByte(r); err != nil {
+				return nil, err
+			}
+			if qi.InlineSize, err = ignite.ReadInt(r); err != nil {
+				return nil, err
+			}
+			m, err := ignite.ReadInt(r)
+			if err != nil {
+				return nil, err
+			}
+			for k := 0; k < int(m); k++ {
+				var f ignite.Field
+				if f.Name, err = ignite.ReadOString(r); err != nil {
+					return nil, err
+				}
+				if f.IsDescensing, err = ignite.ReadBool(r); err != nil {
+					return nil, err
+				}
+				qi.Fields = append(qi.Fields, f)
+			}
+			qe.QueryIndexes = append(qe.QueryIndexes, qi)
+		}
+
+		qes = append(qes, qe)
+	}
+	return qes, nil
+}
+
+// writeCacheConfiguration writes response of OP_CACHE_GET_CONFIGURATION
+func writeCacheConfiguration(w io.Writer, v ignite.ProtocolVersion, cc ignite.CacheConfiguration) error {
+	b := &errWriter{}
+	b.int(cc.AtomicityMode)
+	b.int(cc.Backups)
+	b.int(cc.CacheMode)
+	b.bool(cc.CopyOnRead)
+	b.string(cc.DataRegionName)
+	b.bool(cc.EagerTTL)
+	b.bool(cc.EnableStatistics)
+	b.string(cc.GroupName)
+	b.long(cc.LockTimeout)
+	b.int(cc.MaxConcurrentAsyncOperations)
+	b.int(cc.MaxQueryIterators)
+	b.string(cc.Name)
+	b.bool(cc.OnheapCacheEnabled)
+	b.int(cc.PartitionLossPolicy)
+	b.int(cc.QueryDetailMetricsSize)
+	b.int(cc.QueryParellelism)
+	b.bool(cc.ReadFromBackup)
+	b.int(cc.RebalanceBatchSize)
+	b.long(cc.RebalanceBatchesPrefetchCount)
+	b.long(cc.RebalanceDelay)
+	b.int(cc.RebalanceMode)
+	b.int(cc.RebalanceOrder)
+	b.long(cc.RebalanceThrottle)
+	b.long(cc.RebalanceTimeout)
+	b.bool(cc.SQLEscapeAll)
+	b.int(cc.SQLIndexInlineMaxSize)
+	b.string(cc.SQLSchema)
+	b.int(cc.WriteSynchronizationMode)
+
+	b.int(int32(len(cc.CacheKeyConfigurations)))
+	for _, ckc := range cc.CacheKeyConfigurations {
+		b.string(ckc.TypeName)
+		b.string(ckc.AffinityKeyFieldName)
+	}
+
+	b.int(int32(len(cc.QueryEntities)))
+	for _, qe := range cc.QueryEntities {
+		b.string(qe.KeyTypeName)
+		b.string(qe.ValueTypeName)
+		b.string(qe.TableName)
+		b.string(qe.KeyFieldName)
+		b.string(qe.ValueFieldName)
+		b.int(int32(len(qe.QueryFields)))
+		for _, qf := range qe.QueryFields {
+			b.string(qf.Name)
+			b.string(qf.TypeName)
+			b.bool(qf.IsKeyField)
+			b.bool(qf.IsNotNullConstraintField)
+		}
+		b.int(int32(len(qe.FieldNameAliases)))
+		for _, fna := range qe.FieldNameAliases {
+			b.string(fna.Name)
+			b.string(fna.Alias)
+		}
+		b.int(int32(len(qe.QueryIndexes)))
+		for _, qi := range qe.QueryIndexes {
+			b.string(qi.Name)
+			b.byte(qi.Type)
+			b.int(qi.InlineSize)
+			b.int(int32(len(qi.Fields)))
+			for _, f := range qi.Fields {
+				b.string(f.Name)
+				b.bool(f.IsDescensing)
+			}
+		}
+	}
+
+	if v.AtLeast(ignite.ProtocolVersion160) {
+		b.bool(cc.ExpiryPolicy != nil)
+		if p := cc.ExpiryPolicy; p != nil {
+			b.long(durationToMillis(p.Create))
+			b.long(durationToMillis(p.Update))
+			b.long(durationToMillis(p.Access))
+		}
+	}
+
+	if b.err != nil {
+		return b.err
+	}
+	if err := ignite.WriteInt(w, int32(b.buf.Len())); err != nil {
+		return err
+	}
+	_, err := b.buf.WriteTo(w)
+	return err
+}
+
+// durationFromMillis converts expiry policy duration from protocol format
+func durationFromMillis(ms int64) time.Duration {
+	switch ms {
+	case -2:
+		return ignite.ExpiryUnchanged
+	case -1:
+		return ignite.ExpiryEternal
+	case 0:
+		return ignite.ExpiryImmediately
+	default:
+		return time.Duration(ms) * time.Millisecond
+	}
+}
+
+// durationToMillis converts expiry policy duration to protocol format
+func durationToMillis(d time.Duration) int64 {
+	switch d {
+	case ignite.ExpiryUnchanged:
+		return -2
+	case ignite.ExpiryEternal:
+		return -1
+	case ignite.ExpiryImmediately:
+		return 0
+	default:
+		return int64(d / time.Millisecond)
+	}
+}
+
+// errWriter writes values to buffer and keeps the first error
+type errWriter struct {
+	buf bytes.Buffer
+	err error
+}
+
+func (w *errWriter) byte(v byte) {
+	if w.err == nil {
+		w.err = ignite.WriteByte(&w.buf, v)
+	}
+}
+
+func (w *errWriter) bool(v bool) {
+	if w.err == nil {
+		w.err = ignite.WriteBool(&w.buf, v)
+	}
+}
+
+func (w *errWriter) int(v int32) {
+	if w.err == nil {
+		w.err = ignite.WriteInt(&w.buf, v)
+	}
+}
+
+func (w *errWriter) long(v int64) {
+	if w.err == nil {
+		w.err = ignite.WriteLong(&w.buf, v)
+	}
+}
+
+func (w *errWriter) string(v string) {
+	if w.err == nil {
+		w.err = ignite.WriteOString(&w.buf, v)
+	}
+}
@@ -0,0 +1,411 @@
+package ignitetest
+
+import (
+	"bytes"
+	"io"
+	"sort"
+
+	"github.com/amsokol/ignite-go-client/binary/v1"
+)
+
+const (
+	cacheFlagWithExpiryPolicy = 4
+)
+
+// handle executes operation and writes response body
+func (s *Server) handle(v ignite.ProtocolVersion, code int16, r io.Reader, w io.Writer) error {
+	if code == ignite.OpQuerySQLFields {
+		// SQL handler is called without lock
+		return s.querySQLFields(r, w)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch code {
+	case ignite.OpHeartbeat:
+		return nil
+	case ignite.OpGetIdleTimeout:
+		return ignite.WriteLong(w, 0)
+	case ignite.OpCacheGetNames:
+		return s.cacheGetNames(w)
+	case ignite.OpCacheCreateWithName, ignite.OpCacheGetOrCreateWithName:
+		name, err := ignite.ReadOString(r)
+		if err != nil {
+			return err
+		}
+		return s.createCache(defaultCacheConfiguration(name), code == ignite.OpCacheGetOrCreateWithName)
+	case ignite.OpCacheCreateWithConfiguration, ignite.OpCacheGetOrCreateWithConfiguration:
+		cc, err := readCacheConfiguration(r)
+		if err != nil {
+			return err
+		}
+		return s.createCache(cc, code == ignite.OpCacheGetOrCreateWithConfiguration)
+	case ignite.OpCacheGetConfiguration:
+		c, err := s.readCache(r)
+		if err != nil {
+			return err
+		}
+		if _, err = ignite.ReadByte(r); err != nil {
+			return err
+		}
+		return writeCacheConfiguration(w, v, c.config)
+	case ignite.OpCacheDestroy:
+		id, err := ignite.ReadInt(r)
+		if err != nil {
+			return err
+		}
+		if _, ok := s.caches[id]; !ok {
+			return newStatusError(statusCacheDoesNotExist, "cache with ID %d does not exist", id)
+		}
+		delete(s.caches, id)
+		return nil
+	case ignite.OpQueryScan:
+		return s.queryScan(r, w)
+	case ignite.OpQueryScanCursorGetPage, ignite.OpQuerySQLFieldsCursorGetPage:
+		id, err := ignite.ReadLong(r)
+		if err != nil {
+			return err
+		}
+		return s.cursorGetPage(id, w)
+	case ignite.OpResourceClose:
+		id, err := ignite.ReadLong(r)
+		if err != nil {
+			return err
+		}
+		if _, ok := s.cursors[id]; !ok {
+			return newStatusError(statusResourceDoesNotExist, "cursor with ID %d does not exist", id)
+		}
+		delete(s.cursors, id)
+		return nil
+	}
+
+	if code >= ignite.OpCacheGet && code <= ignite.OpCacheGetSize {
+		c, err := s.readCache(r)
+		if err != nil {
+			return err
+		}
+		if err = readCacheFlags(r); err != nil {
+			return err
+		}
+		return c.handle(code, r, w)
+	}
+
+	return newStatusError(statusInvalidOpCode, "operation with code %d is not supported by test server", code)
+}
+
+// cacheGetNames writes names of the caches
+func (s *Server) cacheGetNames(w io.Writer) error {
+	names := make([]string, 0, len(s.caches))
+	for _, c := range s.caches {
+		names = append(names, c.config.Name)
+	}
+	sort.Strings(names)
+	if err := ignite.WriteInt(w, int32(len(names))); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := ignite.WriteOString(w, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createCache creates cache.
+// Must be called under lock.
+func (s *Server) createCache(cc ignite.CacheConfiguration, ignoreExisting bool) error {
+	if len(cc.Name) == 0 {
+		return newStatusError(statusFailed, "cache name must not be empty")
+	}
+	id := ignite.HashCode(cc.Name)
+	if _, ok := s.caches[id]; ok {
+		if ignoreExisting {
+			return nil
+		}
+		return newStatusError(statusCacheExists, "cache \"%s\" already exists", cc.Name)
+	}
+	s.caches[id] = &cache{config: cc, entries: map[string]entry{}}
+	return nil
+}
+
+// readCache reads cache ID and returns the cache.
+// Must be called under lock.
+func (s *Server) readCache(r io.Reader) (*cache, error) {
+	id, err := ignite.ReadInt(r)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := s.caches[id]
+	if !ok {
+		return nil, newStatusError(statusCacheDoesNotExist, "cache with ID %d does not exist", id)
+	}
+	return c, nil
+}
+
+// readCacheFlags reads flags of key-value operation and skips expiry policy
+func readCacheFlags(r io.Reader) error {
+	flags, err := ignite.ReadByte(r)
+	if err != nil {
+		return err
+	}
+	if flags&cacheFlagWithExpiryPolicy != 0 {
+		// expiry policy is not supported, entries never expire
+		for i := 0; i < 3; i++ {
+			if _, err = ignite.ReadLong(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readRaw reads object in serialized form
+func readRaw(r io.Reader) ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := ignite.ReadObject(io.TeeReader(r, &b)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// readRawKey reads key in serialized form
+func readRawKey(r io.Reader) ([]byte, error) {
+	k, err := readRaw(r)
+	if err != nil {
+		return nil, err
+	}
+	if isNull(k) {
+		return nil, newStatusError(statusFailed, "key must not be null")
+	}
+	return k, nil
+}
+
+// readRawKeys reads count of keys and keys in serialized form
+func readRawKeys(r io.Reader) ([][]byte, error) {
+	count, err := ignite.ReadInt(r)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([][]byte, 0, int(count))
+	for i := 0; i < int(count); i++ {
+		k, err := readRawKey(r)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// isNull returns true if serialized object is NULL
+func isNull(b []byte) bool {
+	return len(b) == 1 && b[0] == typeNULL
+}
+
+// writeRaw writes serialized object or NULL if object is absent
+func writeRaw(w io.Writer, b []byte) error {
+	if b == nil {
+		return ignite.WriteNull(w)
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// get returns serialized value or nil if entry is absent
+func (c *cache) get(k []byte) []byte {
+	if e, ok := c.entries[string(k)]; ok {
+		return e.value
+	}
+	return nil
+}
+
+// put stores the entry or removes it if value is NULL
+func (c *cache) put(k, v []byte) {
+	if isNull(v) {
+		delete(c.entries, string(k))
+		return
+	}
+	c.entries[string(k)] = entry{key: k, value: v}
+}
+
+// remove removes the entry and returns true if it existed
+func (c *cache) remove(k []byte) bool {
+	_, ok := c.entries[string(k)]
+	delete(c.entries, string(k))
+	return ok
+}
+
+// handle executes key-value operation
+func (c *cache) handle(code int16, r io.Reader, w io.Writer) error {
+	switch code {
+	case ignite.OpCacheGet, ignite.OpCacheContainsKey, ignite.OpCacheGetAndRemove,
+		ignite.OpCacheClearKey, ignite.OpCacheRemoveKey:
+		k, err := readRawKey(r)
+		if err != nil {
+			return err
+		}
+		old := c.get(k)
+		switch code {
+		case ignite.OpCacheGet:
+			return writeRaw(w, old)
+		case ignite.OpCacheContainsKey:
+			return ignite.WriteBool(w, old != nil)
+		case ignite.OpCacheGetAndRemove:
+			c.remove(k)
+			return writeRaw(w, old)
+		case ignite.OpCacheClearKey:
+			c.remove(k)
+			return nil
+		default:
+			return ignite.WriteBool(w, c.remove(k))
+		}
+
+	case ignite.OpCachePut, ignite.OpCachePutIfAbsent, ignite.OpCacheGetAndPut, ignite.OpCacheGetAndReplace,
+		ignite.OpCacheGetAndPutIfAbsent, ignite.OpCacheReplace, ignite.OpCacheRemoveIfEquals:
+		k, err := readRawKey(r)
+		if err != nil {
+			return err
+		}
+		v, err := readRaw(r)
+		if err != nil {
+			return err
+		}
+		old := c.get(k)
+		switch code {
+		case ignite.OpCachePut:
+			c.put(k, v)
+			return nil
+		case ignite.OpCachePutIfAbsent:
+			if old == nil {
+				c.put(k, v)
+			}
+			return ignite.WriteBool(w, old == nil)
+		case ignite.OpCacheGetAndPut:
+			c.put(k, v)
+			return writeRaw(w, old)
+		case ignite.OpCacheGetAndReplace:
+			if old != nil {
+				c.put(k, v)
+			}
+			return writeRaw(w, old)
+		case ignite.OpCacheGetAndPutIfAbsent:
+			if old == nil {
+				c.put(k, v)
+			}
+			return writeRaw(w, old)
+		case ignite.OpCacheReplace:
+			if old != nil {
+				c.put(k, v)
+			}
+			return ignite.WriteBool(w, old != nil)
+		default:
+			ok := old != nil && bytes.Equal(old, v)
+			if ok {
+				c.remove(k)
+			}
+			return ignite.WriteBool(w, ok)
+		}
+
+	case ignite.OpCacheReplaceIfEquals:
+		k, err := readRawKey(r)
+		if err != nil {
+			return err
+		}
+		cmp, err := readRaw(r)
+		if err != nil {
+			return err
+		}
+		v, err := readRaw(r)
+		if err != nil {
+			return err
+		}
+		old := c.get(k)
+		ok := old != nil && bytes.Equal(old, cmp)
+		if ok {
+			c.put(k, v)
+		}
+		return ignite.WriteBool(w, ok)
+
+	case ignite.OpCacheGetAll:
+		keys, err := readRawKeys(r)
+		if err != nil {
+			return err
+		}
+		found := make([]entry, 0, len(keys))
+		for _, k := range keys {
+			if v := c.get(k); v != nil {
+				found = append(found, entry{key: k, value: v})
+			}
+		}
+		if err = ignite.WriteInt(w, int32(len(found))); err != nil {
+			return err
+		}
+		for _, e := range found {
+			if err = writeRaw(w, e.key); err != nil {
+				return err
+			}
+			if err = writeRaw(w, e.value); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case ignite.OpCacheContainsKeys:
+		keys, err := readRawKeys(r)
+		if err != nil {
+			return err
+		}
+		ok := true
+		for _, k := range keys {
+			ok = ok && c.get(k) != nil
+		}
+		return ignite.WriteBool(w, ok)
+
+	case ignite.OpCacheClearKeys, ignite.OpCacheRemoveKeys:
+		keys, err := readRawKeys(r)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			c.remove(k)
+		}
+		return nil
+
+	case ignite.OpCachePutAll:
+		count, err := ignite.ReadInt(r)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < int(count); i++ {
+			k, err := readRawKey(r)
+			if err != nil {
+				return err
+			}
+			v, err := readRaw(r)
+			if err != nil {
+				return err
+			}
+			c.put(k, v)
+		}
+		return nil
+
+	case ignite.OpCacheClear, ignite.OpCacheRemoveAll:
+		c.entries = map[string]entry{}
+		return nil
+
+	case ignite.OpCacheGetSize:
+		count, err := ignite.ReadInt(r)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < int(count); i++ {
+			if _, err = ignite.ReadByte(r); err != nil {
+				return err
+			}
+		}
+		return ignite.WriteLong(w, int64(len(c.entries)))
+	}
+
+	return newStatusError(statusInvalidOpCode, "operation with code %d is not supported by test server", code)
+}
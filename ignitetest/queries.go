@@ -0,0 +1,220 @@
+package ignitetest
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/amsokol/ignite-go-client/binary/v1"
+)
+
+// defaultPageSize is used if client requested page size is not positive
+const defaultPageSize = 1024
+
+// queryScan executes scan query.
+// Must be called under lock.
+func (s *Server) queryScan(r io.Reader, w io.Writer) error {
+	c, err := s.readCache(r)
+	if err != nil {
+		return err
+	}
+	if _, err = ignite.ReadBool(r); err != nil {
+		return err
+	}
+	filter, err := readRaw(r)
+	if err != nil {
+		return err
+	}
+	if !isNull(filter) {
+		return newStatusError(statusFailed, "scan query filter is not supported by test server")
+	}
+	pageSize, err := ignite.ReadInt(r)
+	if err != nil {
+		return err
+	}
+	if _, err = ignite.ReadInt(r); err != nil {
+		return err
+	}
+	if _, err = ignite.ReadBool(r); err != nil {
+		return err
+	}
+
+	// snapshot of the entries sorted by serialized key
+	rows := make([][][]byte, 0, len(c.entries))
+	for _, e := range c.entries {
+		rows = append(rows, [][]byte{e.key, e.value})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return bytes.Compare(rows[i][0], rows[j][0]) < 0
+	})
+
+	return s.openCursor(&cursor{pageSize: int(pageSize), rows: rows}, w)
+}
+
+// querySQLFields executes SQL fields query with SQL handler
+func (s *Server) querySQLFields(r io.Reader, w io.Writer) error {
+	var q SQLQuery
+
+	id, err := ignite.ReadInt(r)
+	if err != nil {
+		return err
+	}
+	if _, err = ignite.ReadBool(r); err != nil {
+		return err
+	}
+	if q.Schema, err = ignite.ReadOString(r); err != nil {
+		return err
+	}
+	pageSize, err := ignite.ReadInt(r)
+	if err != nil {
+		return err
+	}
+	maxRows, err := ignite.ReadInt(r)
+	if err != nil {
+		return err
+	}
+	if q.Query, err = ignite.ReadOString(r); err != nil {
+		return err
+	}
+	count, err := ignite.ReadInt(r)
+	if err != nil {
+		return err
+	}
+	q.Args = make([]interface{}, 0, int(count))
+	for i := 0; i < int(count); i++ {
+		a, err := ignite.ReadObject(r)
+		if err != nil {
+			return err
+		}
+		q.Args = append(q.Args, a)
+	}
+	if q.StatementType, err = ignite.ReadByte(r); err != nil {
+		return err
+	}
+	q.PageSize, q.MaxRows = int(pageSize), int(maxRows)
+	for _, flag := range []*bool{&q.DistributedJoins, &q.LocalQuery, &q.ReplicatedOnly,
+		&q.EnforceJoinOrder, &q.Collocated, &q.LazyQuery} {
+		if *flag, err = ignite.ReadBool(r); err != nil {
+			return err
+		}
+	}
+	timeout, err := ignite.ReadLong(r)
+	if err != nil {
+		return err
+	}
+	q.Timeout = time.Duration(timeout) * time.Millisecond
+	includeFieldNames, err := ignite.ReadBool(r)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	if c, ok := s.caches[id]; ok {
+		q.Cache = c.config.Name
+	}
+	s.mutex.Unlock()
+
+	if s.SQL == nil {
+		return newStatusError(statusFailed, "SQL queries are not supported by test server")
+	}
+	res, err := s.SQL(q)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][][]byte, 0, len(res.Rows))
+	for i, row := range res.Rows {
+		if maxRows > 0 && i >= int(maxRows) {
+			break
+		}
+		if len(row) != len(res.Columns) {
+			return newStatusError(statusFailed, "row with index %d has %d fields, but result has %d columns",
+				i, len(row), len(res.Columns))
+		}
+		fields := make([][]byte, 0, len(row))
+		for _, f := range row {
+			var b bytes.Buffer
+			if err = ignite.WriteObject(&b, f); err != nil {
+				return err
+			}
+			fields = append(fields, b.Bytes())
+		}
+		rows = append(rows, fields)
+	}
+
+	// write header of the first page
+	var b bytes.Buffer
+	if err = ignite.WriteInt(&b, int32(len(res.Columns))); err != nil {
+		return err
+	}
+	if includeFieldNames {
+		for _, name := range res.Columns {
+			if err = ignite.WriteOString(&b, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.openCursor(&cursor{pageSize: int(pageSize), header: b.Bytes(), rows: rows}, w)
+}
+
+// openCursor writes cursor ID and the first page.
+// Cursor is registered only if there are more pages.
+// Must be called under lock.
+func (s *Server) openCursor(c *cursor, w io.Writer) error {
+	if c.pageSize <= 0 {
+		c.pageSize = defaultPageSize
+	}
+	var id int64
+	if len(c.rows) > c.pageSize {
+		s.cursor++
+		id = s.cursor
+		s.cursors[id] = c
+	}
+	if err := ignite.WriteLong(w, id); err != nil {
+		return err
+	}
+	if _, err := w.Write(c.header); err != nil {
+		return err
+	}
+	return c.writePage(w)
+}
+
+// cursorGetPage writes the next page of the cursor.
+// Must be called under lock.
+func (s *Server) cursorGetPage(id int64, w io.Writer) error {
+	c, ok := s.cursors[id]
+	if !ok {
+		return newStatusError(statusResourceDoesNotExist, "cursor with ID %d does not exist", id)
+	}
+	if err := c.writePage(w); err != nil {
+		return err
+	}
+	if len(c.rows) == 0 {
+		delete(s.cursors, id)
+	}
+	return nil
+}
+
+// writePage writes row count, rows and has more flag of the next page
+func (c *cursor) writePage(w io.Writer) error {
+	n := c.pageSize
+	if n > len(c.rows) {
+		n = len(c.rows)
+	}
+	if err := ignite.WriteInt(w, int32(n)); err != nil {
+		return err
+	}
+	for _, row := range c.rows[:n] {
+		for _, f := range row {
+			if _, err := w.Write(f); err != nil {
+				return err
+			}
+		}
+	}
+	c.rows = c.rows[n:]
+	return ignite.WriteBool(w, len(c.rows) > 0)
+}
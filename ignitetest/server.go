@@ -0,0 +1,380 @@
+// Package ignitetest provides in-memory fake of Apache Ignite server
+// speaking the binary client protocol, so client code can be tested without JVM.
+//
+// Server supports handshake, cache configuration, key-value, scan query and cursor operations.
+// SQL queries are answered by SQLHandler provided by test.
+// Keys and values are compared in serialized form, so the same Go types
+// must be used to put and get the entry.
+package ignitetest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+	"github.com/amsokol/ignite-go-client/binary/v1"
+)
+
+// MaxProtocolVersion is the latest protocol version supported by test server
+var MaxProtocolVersion = ignite.ProtocolVersion170
+
+const (
+	statusSuccess              = 0
+	statusFailed               = 1
+	statusInvalidOpCode        = 2
+	statusCacheDoesNotExist    = 1000
+	statusCacheExists          = 1001
+	statusResourceDoesNotExist = 1011
+
+	typeNULL = 101
+
+	// maxMessageLength is limit of the message length accepted by server
+	maxMessageLength = 64 << 20
+)
+
+// SQLQuery is SQL fields query received by server
+type SQLQuery struct {
+	// Cache is name of the cache the query is executed in context of
+	Cache string
+
+	// Schema of the query
+	Schema string
+
+	// Query text
+	Query string
+
+	// Args is query arguments
+	Args []interface{}
+
+	// StatementType is ignite.StatementTypeAny, ignite.StatementTypeSelect or ignite.StatementTypeUpdate
+	StatementType byte
+
+	// PageSize is cursor page size requested by client
+	PageSize int
+
+	// MaxRows is max count of the rows requested by client, zero means no limit
+	MaxRows int
+
+	// Timeout is query timeout requested by client, zero means no timeout
+	Timeout time.Duration
+
+	// Query flags requested by client
+	DistributedJoins bool
+	LocalQuery       bool
+	ReplicatedOnly   bool
+	EnforceJoinOrder bool
+	Collocated       bool
+	LazyQuery        bool
+}
+
+// SQLResult is result of SQL fields query
+type SQLResult struct {
+	// Columns is names of the result set columns
+	Columns []string
+
+	// Rows of the result set.
+	// Result of DML statement is one row with one int64 column (count of affected rows).
+	Rows [][]interface{}
+}
+
+// SQLHandler returns result of SQL fields query.
+// Error is sent to client as failed operation status.
+type SQLHandler func(q SQLQuery) (*SQLResult, error)
+
+// Server is in-memory fake of Apache Ignite server
+type Server struct {
+	// SQL handles OP_QUERY_SQL_FIELDS queries.
+	// Nil value means SQL queries are not supported.
+	SQL SQLHandler
+
+	listener net.Listener
+	nodeID   uuid.UUID
+
+	mutex   sync.Mutex
+	caches  map[int32]*cache
+	cursors map[int64]*cursor
+	cursor  int64
+	conns   map[net.Conn]struct{}
+	closed  bool
+
+	wg sync.WaitGroup
+}
+
+// entry is cache entry in serialized form
+type entry struct {
+	key   []byte
+	value []byte
+}
+
+type cache struct {
+	config  ignite.CacheConfiguration
+	entries map[string]entry
+}
+
+// cursor is open query cursor
+type cursor struct {
+	pageSize int
+	// header is written before the first page (field names of SQL query result)
+	header []byte
+	// rows is remaining rows: key and value for scan query, fields for SQL query
+	rows [][][]byte
+}
+
+// NewServer starts test server on random local port.
+func NewServer() (*Server, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to listen")
+	}
+	s := &Server{
+		listener: l,
+		nodeID:   uuid.New(),
+		caches:   map[int32]*cache{},
+		cursors:  map[int64]*cursor{},
+		conns:    map[net.Conn]struct{}{},
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr returns address server listens on
+func (s *Server) Addr() *net.TCPAddr {
+	return s.listener.Addr().(*net.TCPAddr)
+}
+
+// ConnInfo returns connection info to connect to server with the latest supported protocol version
+func (s *Server) ConnInfo() ignite.ConnInfo {
+	return ignite.ConnInfo{
+		Network: "tcp",
+		Host:    s.Addr().IP.String(),
+		Port:    s.Addr().Port,
+		Major:   MaxProtocolVersion.Major,
+		Minor:   MaxProtocolVersion.Minor,
+		Patch:   MaxProtocolVersion.Patch,
+	}
+}
+
+// CacheNames returns names of the caches sorted by name
+func (s *Server) CacheNames() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	names := make([]string, 0, len(s.caches))
+	for _, c := range s.caches {
+		names = append(names, c.config.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenCursors returns count of the open query cursors
+func (s *Server) OpenCursors() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.cursors)
+}
+
+// Close stops server and closes client connections
+func (s *Server) Close() error {
+	s.mutex.Lock()
+	s.closed = true
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mutex.Unlock()
+
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+// serve accepts connections
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		if s.closed {
+			s.mutex.Unlock()
+			c.Close()
+			return
+		}
+		s.conns[c] = struct{}{}
+		s.mutex.Unlock()
+
+		s.wg.Add(1)
+		go s.serveConn(c)
+	}
+}
+
+// serveConn handles handshake and operations of the connection
+func (s *Server) serveConn(c net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mutex.Lock()
+		delete(s.conns, c)
+		s.mutex.Unlock()
+		c.Close()
+	}()
+
+	v, ok := s.handshake(c)
+	if !ok {
+		return
+	}
+	for {
+		b, err := readMessage(c)
+		if err != nil {
+			return
+		}
+		r := bytes.NewReader(b)
+		code, err := ignite.ReadShort(r)
+		if err != nil {
+			return
+		}
+		uid, err := ignite.ReadLong(r)
+		if err != nil {
+			return
+		}
+
+		res := &bytes.Buffer{}
+		err = s.handle(v, code, r, res)
+		if err := writeResponse(c, v, uid, err, res.Bytes()); err != nil {
+			return
+		}
+	}
+}
+
+// handshake negotiates protocol version
+func (s *Server) handshake(c net.Conn) (ignite.ProtocolVersion, bool) {
+	var v ignite.ProtocolVersion
+
+	b, err := readMessage(c)
+	if err != nil {
+		return v, false
+	}
+	r := bytes.NewReader(b)
+	if code, err := ignite.ReadByte(r); err != nil || code != 1 {
+		return v, false
+	}
+	var parts [3]int16
+	for i := range parts {
+		if parts[i], err = ignite.ReadShort(r); err != nil {
+			return v, false
+		}
+	}
+	v = ignite.ProtocolVersion{Major: int(parts[0]), Minor: int(parts[1]), Patch: int(parts[2])}
+
+	res := &bytes.Buffer{}
+	if v.Compare(MaxProtocolVersion) > 0 || v.Major != 1 {
+		ignite.WriteBool(res, false)
+		ignite.WriteShort(res, int16(MaxProtocolVersion.Major))
+		ignite.WriteShort(res, int16(MaxProtocolVersion.Minor))
+		ignite.WriteShort(res, int16(MaxProtocolVersion.Patch))
+		ignite.WriteOString(res, "unsupported protocol version "+v.String())
+		writeMessage(c, res.Bytes())
+		return v, false
+	}
+
+	ignite.WriteBool(res, true)
+	if v.AtLeast(ignite.ProtocolVersion170) {
+		ignite.WriteOArrayBytes(res, features(ignite.FeatureHeartbeat))
+	}
+	if v.AtLeast(ignite.ProtocolVersion140) {
+		ignite.WriteOUUID(res, s.nodeID)
+	}
+	return v, writeMessage(c, res.Bytes()) == nil
+}
+
+// features returns bit mask of the features supported by server
+func features(ids ...int) []byte {
+	var b []byte
+	for _, id := range ids {
+		for len(b) <= id/8 {
+			b = append(b, 0)
+		}
+		b[id/8] |= 1 << uint(id%8)
+	}
+	return b
+}
+
+// readMessage reads length prefixed message
+func readMessage(r io.Reader) ([]byte, error) {
+	var l int32
+	if err := binary.Read(r, binary.LittleEndian, &l); err != nil {
+		return nil, err
+	}
+	if l < 0 || l > maxMessageLength {
+		return nil, errors.Errorf("invalid message length %d (expected from 0 to %d)", l, maxMessageLength)
+	}
+	b := make([]byte, l)
+	_, err := io.ReadFull(r, b)
+	return b, err
+}
+
+// writeMessage writes length prefixed message
+func writeMessage(w io.Writer, b []byte) error {
+	if err := ignite.WriteInt(w, int32(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// statusError is operation error with status code
+type statusError struct {
+	status  int32
+	message string
+}
+
+func (e *statusError) Error() string {
+	return e.message
+}
+
+// newStatusError creates operation error with status code
+func newStatusError(status int32, format string, args ...interface{}) error {
+	return &statusError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+// writeResponse writes operation response.
+// Operation status is taken from the error.
+func writeResponse(w io.Writer, v ignite.ProtocolVersion, uid int64, err error, body []byte) error {
+	var status int32 = statusSuccess
+	if err != nil {
+		status = statusFailed
+		if e, ok := err.(*statusError); ok {
+			status = e.status
+		}
+	}
+
+	res := &bytes.Buffer{}
+	ignite.WriteLong(res, uid)
+	if v.AtLeast(ignite.ProtocolVersion140) {
+		if status == statusSuccess {
+			ignite.WriteShort(res, 0)
+		} else {
+			ignite.WriteShort(res, ignite.ResponseFlagError)
+		}
+	}
+	if !v.AtLeast(ignite.ProtocolVersion140) || status != statusSuccess {
+		ignite.WriteInt(res, status)
+	}
+	if status != statusSuccess {
+		ignite.WriteOString(res, err.Error())
+	} else {
+		res.Write(body)
+	}
+	return writeMessage(w, res.Bytes())
+}
@@ -0,0 +1,274 @@
+package ignitetest
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/amsokol/ignite-go-client/binary/v1"
+)
+
+func newTestServer(t *testing.T) *Server {
+	s, err := NewServer()
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	return s
+}
+
+func connect(t *testing.T, s *Server, v ignite.ProtocolVersion) ignite.Client {
+	ci := s.ConnInfo()
+	ci.Major, ci.Minor, ci.Patch = v.Major, v.Minor, v.Patch
+	c, err := ignite.Connect(ci)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	return c
+}
+
+func TestServer_KeyValue(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	for _, v := range []ignite.ProtocolVersion{ignite.ProtocolVersion110, ignite.ProtocolVersion140, ignite.ProtocolVersion170} {
+		t.Run(v.String(), func(t *testing.T) {
+			c := connect(t, s, v)
+			defer c.Close()
+
+			cache := "KeyValue" + v.String()
+			if err := c.CacheCreateWithName(cache); err != nil {
+				t.Fatalf("CacheCreateWithName() error = %v", err)
+			}
+			if err := c.CacheCreateWithName(cache); err == nil {
+				t.Errorf("CacheCreateWithName() error = nil for existing cache")
+			}
+			if err := c.CachePut(cache, false, int32(1), "one"); err != nil {
+				t.Fatalf("CachePut() error = %v", err)
+			}
+			if err := c.CachePutAll(cache, false, map[interface{}]interface{}{int32(2): "two", int32(3): "three"}); err != nil {
+				t.Fatalf("CachePutAll() error = %v", err)
+			}
+			got, err := c.CacheGet(cache, false, int32(1))
+			if err != nil || got != "one" {
+				t.Errorf("CacheGet() = %v, %v, want \"one\"", got, err)
+			}
+			got, err = c.CacheGet(cache, false, int32(4))
+			if err != nil || got != nil {
+				t.Errorf("CacheGet() = %v, %v, want nil", got, err)
+			}
+			all, err := c.CacheGetAll(cache, false, []interface{}{int32(2), int32(3), int32(4)})
+			if want := map[interface{}]interface{}{int32(2): "two", int32(3): "three"}; err != nil || !reflect.DeepEqual(all, want) {
+				t.Errorf("CacheGetAll() = %v, %v, want %v", all, err, want)
+			}
+			ok, err := c.CachePutIfAbsent(cache, false, int32(1), "uno")
+			if err != nil || ok {
+				t.Errorf("CachePutIfAbsent() = %v, %v, want false", ok, err)
+			}
+			ok, err = c.CacheReplaceIfEquals(cache, false, int32(1), "one", "uno")
+			if err != nil || !ok {
+				t.Errorf("CacheReplaceIfEquals() = %v, %v, want true", ok, err)
+			}
+			got, err = c.CacheGetAndRemove(cache, false, int32(1))
+			if err != nil || got != "uno" {
+				t.Errorf("CacheGetAndRemove() = %v, %v, want \"uno\"", got, err)
+			}
+			size, err := c.CacheGetSize(cache, false, nil)
+			if err != nil || size != 2 {
+				t.Errorf("CacheGetSize() = %v, %v, want 2", size, err)
+			}
+			if err = c.CacheRemoveAll(cache, false); err != nil {
+				t.Errorf("CacheRemoveAll() error = %v", err)
+			}
+			ok, err = c.CacheContainsKeys(cache, false, []interface{}{int32(2)})
+			if err != nil || ok {
+				t.Errorf("CacheContainsKeys() = %v, %v, want false", ok, err)
+			}
+			if err = c.CacheDestroy(cache); err != nil {
+				t.Errorf("CacheDestroy() error = %v", err)
+			}
+			if _, err = c.CacheGet(cache, false, int32(1)); err == nil {
+				t.Errorf("CacheGet() error = nil for destroyed cache")
+			}
+		})
+	}
+}
+
+func TestServer_CacheConfiguration(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	c := connect(t, s, ignite.ProtocolVersion170)
+	defer c.Close()
+
+	name := "Configured"
+	backups := int32(2)
+	cc := &ignite.CacheConfigurationRefs{
+		Name:    &name,
+		Backups: &backups,
+		QueryEntities: []ignite.QueryEntity{{
+			KeyTypeName:   "java.lang.Integer",
+			ValueTypeName: "Person",
+			TableName:     "Person",
+			QueryFields:   []ignite.QueryField{{Name: "name", TypeName: "java.lang.String"}},
+		}},
+		ExpiryPolicy: &ignite.ExpiryPolicy{Create: ignite.ExpiryEternal},
+	}
+	if err := c.CacheCreateWithConfiguration(cc); err != nil {
+		t.Fatalf("CacheCreateWithConfiguration() error = %v", err)
+	}
+	if err := c.CacheGetOrCreateWithConfiguration(cc); err != nil {
+		t.Fatalf("CacheGetOrCreateWithConfiguration() error = %v", err)
+	}
+
+	got, err := c.CacheGetConfiguration(name, 0)
+	if err != nil {
+		t.Fatalf("CacheGetConfiguration() error = %v", err)
+	}
+	if got.Name != name || got.Backups != backups || got.CacheMode != ignite.CacheModePartitioned {
+		t.Errorf("CacheGetConfiguration() = %+v", got)
+	}
+	if len(got.QueryEntities) != 1 || got.QueryEntities[0].TableName != "Person" {
+		t.Errorf("CacheGetConfiguration().QueryEntities = %+v", got.QueryEntities)
+	}
+	if got.ExpiryPolicy == nil || *got.ExpiryPolicy != *cc.ExpiryPolicy {
+		t.Errorf("CacheGetConfiguration().ExpiryPolicy = %v, want %v", got.ExpiryPolicy, cc.ExpiryPolicy)
+	}
+
+	names, err := c.CacheGetNames()
+	if err != nil || !reflect.DeepEqual(names, []string{name}) {
+		t.Errorf("CacheGetNames() = %v, %v, want [%s]", names, err, name)
+	}
+}
+
+func TestServer_QueryScan(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	c := connect(t, s, ignite.ProtocolVersion140)
+	defer c.Close()
+
+	cache := "Scan"
+	if err := c.CacheCreateWithName(cache); err != nil {
+		t.Fatalf("CacheCreateWithName() error = %v", err)
+	}
+	want := map[interface{}]interface{}{}
+	for i := int64(0); i < 5; i++ {
+		want[i] = i * 10
+	}
+	if err := c.CachePutAll(cache, false, want); err != nil {
+		t.Fatalf("CachePutAll() error = %v", err)
+	}
+
+	res, err := c.QueryScan(cache, false, ignite.QueryScanData{PageSize: 2})
+	if err != nil {
+		t.Fatalf("QueryScan() error = %v", err)
+	}
+	got := res.Rows
+	for more := res.HasMore; more; {
+		page, err := c.QueryScanCursorGetPage(res.ID)
+		if err != nil {
+			t.Fatalf("QueryScanCursorGetPage() error = %v", err)
+		}
+		for k, v := range page.Rows {
+			got[k] = v
+		}
+		more = page.HasMore
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryScan() rows = %v, want %v", got, want)
+	}
+	if err = c.ResourceClose(res.ID); err == nil {
+		t.Errorf("ResourceClose() error = nil for exhausted cursor")
+	}
+}
+
+func TestServer_QuerySQLFields(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	var query SQLQuery
+	s.SQL = func(q SQLQuery) (*SQLResult, error) {
+		query = q
+		return &SQLResult{
+			Columns: []string{"ID", "NAME"},
+			Rows:    [][]interface{}{{int64(1), "one"}, {int64(2), "two"}, {int64(3), nil}},
+		}, nil
+	}
+
+	c := connect(t, s, ignite.ProtocolVersion110)
+	defer c.Close()
+
+	res, err := c.QuerySQLFields("", false, ignite.QuerySQLFieldsData{
+		Schema:            "PUBLIC",
+		PageSize:          2,
+		Query:             "SELECT ID, NAME FROM T WHERE ID > ?",
+		QueryArgs:         []interface{}{int64(0)},
+		IncludeFieldNames: true,
+	})
+	if err != nil {
+		t.Fatalf("QuerySQLFields() error = %v", err)
+	}
+	want := SQLQuery{Schema: "PUBLIC", Query: "SELECT ID, NAME FROM T WHERE ID > ?", Args: []interface{}{int64(0)}, PageSize: 2}
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("SQLHandler got %+v, want %+v", query, want)
+	}
+	if !reflect.DeepEqual(res.Fields, []string{"ID", "NAME"}) || len(res.Rows) != 2 || !res.HasMore {
+		t.Fatalf("QuerySQLFields() = %+v", res)
+	}
+	page, err := c.QuerySQLFieldsCursorGetPage(res.ID, res.FieldCount)
+	if err != nil {
+		t.Fatalf("QuerySQLFieldsCursorGetPage() error = %v", err)
+	}
+	if !reflect.DeepEqual(page.Rows, [][]interface{}{{int64(3), nil}}) || page.HasMore {
+		t.Errorf("QuerySQLFieldsCursorGetPage() = %+v", page)
+	}
+}
+
+func TestServer_Handshake(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	ci := s.ConnInfo()
+	ci.Minor = 99
+	if _, err := ignite.Connect(ci); err == nil {
+		t.Errorf("Connect() error = nil for unsupported protocol version")
+	}
+
+	ci = s.ConnInfo()
+	ci.Heartbeat = true
+	c, err := ignite.Connect(ci)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer c.Close()
+	if !c.FeatureSupported(ignite.FeatureHeartbeat) {
+		t.Errorf("FeatureSupported(FeatureHeartbeat) = false")
+	}
+}
+
+func Test_readMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		length  int32
+		want    []byte
+		wantErr bool
+	}{
+		{name: "1", length: 2, want: []byte{1, 2}},
+		{name: "2", length: -1, wantErr: true},
+		{name: "3", length: maxMessageLength + 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &bytes.Buffer{}
+			ignite.WriteInt(r, tt.length)
+			r.Write([]byte{1, 2})
+			got, err := readMessage(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("readMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
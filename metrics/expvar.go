@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"expvar"
+)
+
+// ExpvarSink publishes client metrics with "expvar" package.
+// Every metric is float variable of the published map with key of name and labels,
+// e.g. `ignite_client_operations_total{op="OP_CACHE_GET",status="0"}`.
+// Histogram is published as two variables with "_count" and "_sum" name suffixes.
+type ExpvarSink struct {
+	m *expvar.Map
+}
+
+// NewExpvarSink publishes map with the given name and returns sink to fill it.
+// Like expvar.Publish it panics if the name is already registered.
+func NewExpvarSink(name string) *ExpvarSink {
+	return &ExpvarSink{m: expvar.NewMap(name)}
+}
+
+// Map returns published map
+func (s *ExpvarSink) Map() *expvar.Map {
+	return s.m
+}
+
+// AddCounter adds non-negative delta to the counter
+func (s *ExpvarSink) AddCounter(name string, labels map[string]string, delta float64) {
+	s.m.AddFloat(name+labelsKey(labels), delta)
+}
+
+// AddGauge adds delta (may be negative) to the gauge
+func (s *ExpvarSink) AddGauge(name string, labels map[string]string, delta float64) {
+	s.m.AddFloat(name+labelsKey(labels), delta)
+}
+
+// ObserveHistogram adds value to the histogram
+func (s *ExpvarSink) ObserveHistogram(name string, labels map[string]string, value float64) {
+	key := labelsKey(labels)
+	s.m.AddFloat(name+"_count"+key, 1)
+	s.m.AddFloat(name+"_sum"+key, value)
+}
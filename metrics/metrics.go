@@ -0,0 +1,36 @@
+// Package metrics contains sinks of the client metrics (see ConnInfo.Metrics of the binary/v1 package)
+package metrics
+
+import (
+	"sort"
+	"strings"
+)
+
+// labelsKey returns labels in Prometheus text format, e.g. `{op="OP_CACHE_GET",status="0"}`.
+// Labels are sorted by name, empty string is returned for no labels.
+func labelsKey(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for n := range labels {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, n := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(n)
+		b.WriteString(`="`)
+		b.WriteString(labelValueEscaper.Replace(labels[n]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
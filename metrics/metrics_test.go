@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_labelsKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   string
+	}{
+		{
+			name: "1",
+			want: "",
+		},
+		{
+			name:   "2",
+			labels: map[string]string{"status": "0", "op": "OP_CACHE_GET"},
+			want:   `{op="OP_CACHE_GET",status="0"}`,
+		},
+		{
+			name:   "3",
+			labels: map[string]string{"v": "a\"b\\c\nd"},
+			want:   `{v="a\"b\\c\nd"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := labelsKey(tt.labels); got != tt.want {
+				t.Errorf("labelsKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrometheusSink(t *testing.T) {
+	s := NewPrometheusSink(0.1, 1)
+	s.AddCounter("ops_total", map[string]string{"op": "OP_CACHE_GET", "status": "0"}, 1)
+	s.AddCounter("ops_total", map[string]string{"op": "OP_CACHE_GET", "status": "0"}, 2)
+	s.AddCounter("ops_total", map[string]string{"op": "OP_CACHE_PUT", "status": "1000"}, 1)
+	s.AddGauge("connections", nil, 2)
+	s.AddGauge("connections", nil, -1)
+	s.ObserveHistogram("duration_seconds", map[string]string{"op": "OP_CACHE_GET"}, 0.05)
+	s.ObserveHistogram("duration_seconds", map[string]string{"op": "OP_CACHE_GET"}, 0.5)
+	s.ObserveHistogram("duration_seconds", map[string]string{"op": "OP_CACHE_GET"}, 5)
+
+	want := `# TYPE connections gauge
+connections 1
+# TYPE duration_seconds histogram
+duration_seconds_bucket{op="OP_CACHE_GET",le="0.1"} 1
+duration_seconds_bucket{op="OP_CACHE_GET",le="1"} 2
+duration_seconds_bucket{op="OP_CACHE_GET",le="+Inf"} 3
+duration_seconds_sum{op="OP_CACHE_GET"} 5.55
+duration_seconds_count{op="OP_CACHE_GET"} 3
+# TYPE ops_total counter
+ops_total{op="OP_CACHE_GET",status="0"} 3
+ops_total{op="OP_CACHE_PUT",status="1000"} 1
+`
+	b := &bytes.Buffer{}
+	n, err := s.WriteTo(b)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if got := b.String(); got != want || n != int64(len(want)) {
+		t.Errorf("WriteTo() = %d, %q, want %d, %q", n, got, len(want), want)
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if rec.Body.String() != want {
+		t.Errorf("ServeHTTP() body = %q, want %q", rec.Body.String(), want)
+	}
+}
+
+// blockingWriter blocks writes until unblock is closed
+type blockingWriter struct {
+	started chan struct{}
+	unblock chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	close(w.started)
+	<-w.unblock
+	return len(p), nil
+}
+
+func TestPrometheusSink_WriteTo_slowWriter(t *testing.T) {
+	s := NewPrometheusSink()
+	s.AddCounter("ops_total", nil, 1)
+
+	w := &blockingWriter{started: make(chan struct{}), unblock: make(chan struct{})}
+	defer close(w.unblock)
+	go s.WriteTo(w)
+	<-w.started
+
+	// metrics are recorded while slow writer is blocked
+	added := make(chan struct{})
+	go func() {
+		s.AddCounter("ops_total", nil, 1)
+		close(added)
+	}()
+	select {
+	case <-added:
+	case <-time.After(5 * time.Second):
+		t.Errorf("AddCounter() is blocked by WriteTo()")
+	}
+}
+
+func TestExpvarSink(t *testing.T) {
+	s := NewExpvarSink("TestExpvarSink")
+	s.AddCounter("ops_total", map[string]string{"op": "OP_CACHE_GET"}, 2)
+	s.AddGauge("connections", nil, 1)
+	s.ObserveHistogram("duration_seconds", map[string]string{"op": "OP_CACHE_GET"}, 0.5)
+	s.ObserveHistogram("duration_seconds", map[string]string{"op": "OP_CACHE_GET"}, 1.5)
+
+	want := map[string]string{
+		`ops_total{op="OP_CACHE_GET"}`:              "2",
+		"connections":                               "1",
+		`duration_seconds_count{op="OP_CACHE_GET"}`: "2",
+		`duration_seconds_sum{op="OP_CACHE_GET"}`:   "2",
+	}
+	for k, v := range want {
+		got := s.Map().Get(k)
+		if got == nil || got.String() != v {
+			t.Errorf("variable %s = %v, want %v", k, got, v)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultBuckets are upper bounds of histogram buckets in seconds used by NewPrometheusSink if no buckets are provided
+var DefaultBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+// PrometheusSink collects client metrics and exposes them in Prometheus text format.
+// PrometheusSink is http.Handler, register it for the "/metrics" path of your HTTP server.
+type PrometheusSink struct {
+	buckets []float64
+
+	mutex    sync.Mutex
+	families map[string]*family
+}
+
+// family is metrics with the same name
+type family struct {
+	typ    string
+	series map[string]*series
+}
+
+// series is metric with the same labels
+type series struct {
+	value float64
+	// counts of the histogram buckets, the last one is "+Inf" bucket
+	counts []uint64
+	count  uint64
+}
+
+// NewPrometheusSink returns sink with the given histogram buckets (DefaultBuckets if empty).
+// Buckets must be sorted in increasing order.
+func NewPrometheusSink(buckets ...float64) *PrometheusSink {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	return &PrometheusSink{buckets: buckets, families: map[string]*family{}}
+}
+
+// AddCounter adds non-negative delta to the counter
+func (s *PrometheusSink) AddCounter(name string, labels map[string]string, delta float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.series(name, typeCounter, labels).value += delta
+}
+
+// AddGauge adds delta (may be negative) to the gauge
+func (s *PrometheusSink) AddGauge(name string, labels map[string]string, delta float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.series(name, typeGauge, labels).value += delta
+}
+
+// ObserveHistogram adds value to the histogram
+func (s *PrometheusSink) ObserveHistogram(name string, labels map[string]string, value float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	sr := s.series(name, typeHistogram, labels)
+	if sr.counts == nil {
+		sr.counts = make([]uint64, len(s.buckets)+1)
+	}
+	sr.counts[sort.SearchFloat64s(s.buckets, value)]++
+	sr.count++
+	sr.value += value
+}
+
+// series returns series of the metric, creates it if not exists
+func (s *PrometheusSink) series(name string, typ string, labels map[string]string) *series {
+	f, ok := s.families[name]
+	if !ok {
+		f = &family{typ: typ, series: map[string]*series{}}
+		s.families[name] = f
+	}
+	key := labelsKey(labels)
+	sr, ok := f.series[key]
+	if !ok {
+		sr = &series{}
+		f.series[key] = sr
+	}
+	return sr
+}
+
+// snapshot returns copy of the metrics, so they are written without lock
+func (s *PrometheusSink) snapshot() map[string]*family {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	families := make(map[string]*family, len(s.families))
+	for name, f := range s.families {
+		c := &family{typ: f.typ, series: make(map[string]*series, len(f.series))}
+		for key, sr := range f.series {
+			c.series[key] = &series{value: sr.value, counts: append([]uint64(nil), sr.counts...), count: sr.count}
+		}
+		families[name] = c
+	}
+	return families
+}
+
+// WriteTo writes metrics in Prometheus text format
+func (s *PrometheusSink) WriteTo(w io.Writer) (int64, error) {
+	families := s.snapshot()
+
+	cw := &countingWriter{w: w}
+	b := bufio.NewWriter(cw)
+	for _, name := range sortedKeys(families) {
+		f := families[name]
+		b.WriteString("# TYPE " + name + " " + f.typ + "\n")
+		for _, key := range sortedKeys(f.series) {
+			sr := f.series[key]
+			if f.typ != typeHistogram {
+				b.WriteString(name + key + " " + formatFloat(sr.value) + "\n")
+				continue
+			}
+			var cumulative uint64
+			for i, c := range sr.counts {
+				cumulative += c
+				le := "+Inf"
+				if i < len(s.buckets) {
+					le = formatFloat(s.buckets[i])
+				}
+				b.WriteString(name + "_bucket" + withLabel(key, "le", le) + " " +
+					strconv.FormatUint(cumulative, 10) + "\n")
+			}
+			b.WriteString(name + "_sum" + key + " " + formatFloat(sr.value) + "\n")
+			b.WriteString(name + "_count" + key + " " + strconv.FormatUint(sr.count, 10) + "\n")
+		}
+	}
+	err := b.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP writes metrics in Prometheus text format
+func (s *PrometheusSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.WriteTo(w)
+}
+
+// withLabel adds label to labels in Prometheus text format
+func withLabel(key string, name string, value string) string {
+	l := name + `="` + labelValueEscaper.Replace(value) + `"`
+	if key == "" {
+		return "{" + l + "}"
+	}
+	return key[:len(key)-1] + "," + l + "}"
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]*family:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*series:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// countingWriter counts written bytes
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,36 @@
+// +build go1.16
+
+package ignitesql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+	"github.com/amsokol/ignite-go-client/sql/v1"
+)
+
+// ExecBatch executes query for every parameter set of args using connection opened by Apache Ignite driver
+// and returns count of the affected rows for every statement sent to server:
+//
+//	conn, err := db.Conn(ctx)
+//	...
+//	defer conn.Close()
+//	counts, err := ignitesql.ExecBatch(ctx, conn, "INSERT INTO Person(ID, NAME) VALUES(?, ?)",
+//		[][]interface{}{{int64(1), "Alice"}, {int64(2), "Bob"}})
+//
+// INSERT and MERGE queries with single VALUES tuple are sent as multi-row statements
+// with up to v1.DefaultBatchSize tuples, other queries are executed once for every parameter set.
+func ExecBatch(ctx context.Context, conn *sql.Conn, query string, args [][]interface{}) ([]int64, error) {
+	var counts []int64
+	err := conn.Raw(func(driverConn interface{}) error {
+		b, ok := driverConn.(v1.BatchExecer)
+		if !ok {
+			return errors.Errorf("connection %T doesn't support batch execution", driverConn)
+		}
+		var err error
+		counts, err = b.ExecBatchContext(ctx, query, args)
+		return err
+	})
+	return counts, err
+}
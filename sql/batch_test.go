@@ -0,0 +1,53 @@
+// +build go1.16
+
+package ignitesql
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/amsokol/ignite-go-client/ignitetest"
+)
+
+func TestExecBatch(t *testing.T) {
+	srv, err := ignitetest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	defer srv.Close()
+	var queries []string
+	srv.SQL = func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		queries = append(queries, q.Query)
+		return &ignitetest.SQLResult{Columns: []string{"UPDATED"}, Rows: [][]interface{}{{int64(len(q.Args) / 2)}}}, nil
+	}
+
+	cfg := NewConfig()
+	cfg.Port = srv.Addr().Port
+	c, err := NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("NewConnector() error = %v", err)
+	}
+	db := sql.OpenDB(c)
+	defer db.Close()
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Conn() error = %v", err)
+	}
+	defer conn.Close()
+
+	counts, err := ExecBatch(ctx, conn, "INSERT INTO Person(ID, NAME) VALUES(?, ?)",
+		[][]interface{}{{int64(1), "Alice"}, {int64(2), "Bob"}, {int64(3), "Carol"}})
+	if err != nil {
+		t.Fatalf("ExecBatch() error = %v", err)
+	}
+	if !reflect.DeepEqual(counts, []int64{3}) {
+		t.Errorf("ExecBatch() = %v, want [3]", counts)
+	}
+	if want := []string{"INSERT INTO Person(ID, NAME) VALUES(?, ?), (?, ?), (?, ?)"}; !reflect.DeepEqual(queries, want) {
+		t.Errorf("ExecBatch() queries = %q, want %q", queries, want)
+	}
+}
@@ -22,6 +22,9 @@ type ConnInfo struct {
 	// Max rows.
 	MaxRows int
 
+	// Max count of the cursor pages fetched in background. Zero value disables prefetching.
+	PrefetchPages int
+
 	// Timeout(milliseconds) value should be non-negative. Zero value disables timeout.
 	Timeout int64
 
@@ -0,0 +1,130 @@
+package common
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+// SQLSTATE codes detected by the driver
+const (
+	// SQLStateInvalidCursor means cursor does not exist
+	SQLStateInvalidCursor = "24000"
+	// SQLStateAuthFailed means invalid authorization specification
+	SQLStateAuthFailed = "28000"
+	// SQLStateInvalidSchema means schema (cache) does not exist
+	SQLStateInvalidSchema = "3F000"
+	// SQLStateSyntaxError means query can't be parsed
+	SQLStateSyntaxError = "42000"
+	// SQLStateInsufficientPrivilege means operation is not authorized
+	SQLStateInsufficientPrivilege = "42501"
+	// SQLStateTableExists means table already exists
+	SQLStateTableExists = "42S01"
+	// SQLStateTableNotFound means table or view does not exist
+	SQLStateTableNotFound = "42S02"
+	// SQLStateColumnNotFound means column does not exist
+	SQLStateColumnNotFound = "42S22"
+	// SQLStateNotNullViolation means null value is set to not null column or key
+	SQLStateNotNullViolation = "23502"
+	// SQLStateDuplicateKey means row with the same key already exists
+	SQLStateDuplicateKey = "23505"
+	// SQLStateTooManyCursors means server limit of the open cursors is reached
+	SQLStateTooManyCursors = "54000"
+	// SQLStateGeneralError means error without specific SQLSTATE code
+	SQLStateGeneralError = "HY000"
+)
+
+// Error is error of the SQL query returned by server
+type Error struct {
+	// SQLState is five characters SQLSTATE code, empty if it is unknown
+	SQLState string
+
+	// Code is Apache Ignite status code
+	Code int32
+
+	// Message is error message returned by server
+	Message string
+
+	// err is original error
+	err error
+}
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns original error, so binary/errors sentinels (e.g. ErrCacheNotFound) are matched by errors.Is
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// h2ErrorCode matches H2 error code embedded into message, e.g. "Table \"T\" not found; SQL statement: ... [42102-197]"
+var h2ErrorCode = regexp.MustCompile(`\[([0-9A-Z]{5})-[0-9]+\]`)
+
+// h2SQLStates maps H2 error codes which are not SQLSTATE codes
+var h2SQLStates = map[string]string{
+	"42101": SQLStateTableExists,
+	"42102": SQLStateTableNotFound,
+	"42122": SQLStateColumnNotFound,
+	"50000": SQLStateGeneralError,
+	"90079": SQLStateInvalidSchema,
+}
+
+// messageSQLStates maps fragments of Apache Ignite SQL error messages (in lower case)
+var messageSQLStates = []struct {
+	fragment string
+	state    string
+}{
+	{"duplicate key", SQLStateDuplicateKey},
+	{"table already exists", SQLStateTableExists},
+	{"table doesn't exist", SQLStateTableNotFound},
+	{"table not found", SQLStateTableNotFound},
+	{"column doesn't exist", SQLStateColumnNotFound},
+	{"null key is not allowed", SQLStateNotNullViolation},
+	{"null value is not allowed", SQLStateNotNullViolation},
+	{"failed to parse query", SQLStateSyntaxError},
+}
+
+// statusSQLStates maps Apache Ignite status codes
+var statusSQLStates = map[int32]string{
+	errors.StatusCacheDoesNotExist:    SQLStateInvalidSchema,
+	errors.StatusTooManyCursors:       SQLStateTooManyCursors,
+	errors.StatusResourceDoesNotExist: SQLStateInvalidCursor,
+	errors.StatusSecurityViolation:    SQLStateInsufficientPrivilege,
+	errors.StatusAuthFailed:           SQLStateAuthFailed,
+}
+
+// NewError returns Error if err is returned by server, otherwise err is returned unchanged
+func NewError(err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	var ie *errors.IgniteError
+	if !errors.As(err, &ie) {
+		return err
+	}
+	return &Error{SQLState: sqlState(ie.IgniteStatus, ie.IgniteMessage),
+		Code: ie.IgniteStatus, Message: ie.IgniteMessage, err: err}
+}
+
+// sqlState detects SQLSTATE code by H2 error code embedded into message, message text and status code
+func sqlState(status int32, message string) string {
+	if m := h2ErrorCode.FindStringSubmatch(message); m != nil {
+		if s, ok := h2SQLStates[m[1]]; ok {
+			return s
+		}
+		if !strings.HasPrefix(m[1], "9") {
+			// H2 specific codes start with 9, the other codes are SQLSTATE codes
+			return m[1]
+		}
+	}
+	lower := strings.ToLower(message)
+	for _, m := range messageSQLStates {
+		if strings.Contains(lower, m.fragment) {
+			return m.state
+		}
+	}
+	return statusSQLStates[status]
+}
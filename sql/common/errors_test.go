@@ -0,0 +1,101 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+func TestNewError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantState string
+		wantCode  int32
+		wantIs    bool
+	}{
+		{
+			name:      "H2 table not found",
+			err:       errors.NewError(errors.StatusFailed, "Failed to parse query. Table \"T\" not found; SQL statement:\nSELECT * FROM T [42102-197]"),
+			wantState: SQLStateTableNotFound,
+			wantCode:  errors.StatusFailed,
+		},
+		{
+			name:      "H2 syntax error",
+			err:       errors.NewError(errors.StatusFailed, "Syntax error in SQL statement \"SELEC[*] 1\"; SQL statement:\nSELEC 1 [42000-197]"),
+			wantState: SQLStateSyntaxError,
+			wantCode:  errors.StatusFailed,
+		},
+		{
+			name:      "H2 specific code",
+			err:       errors.NewError(errors.StatusFailed, "Failed to parse query. General error [50000-197]"),
+			wantState: SQLStateGeneralError,
+			wantCode:  errors.StatusFailed,
+		},
+		{
+			name:      "Ignite duplicate key",
+			err:       errors.Wrapf(errors.NewError(errors.StatusFailed, "Duplicate key during INSERT [key=KeyCacheObjectImpl [part=1, val=1, hasValBytes=false]]"), "failed to execute query"),
+			wantState: SQLStateDuplicateKey,
+			wantCode:  errors.StatusFailed,
+		},
+		{
+			name:      "Ignite table doesn't exist",
+			err:       errors.NewError(errors.StatusFailed, "Table doesn't exist: PERSON"),
+			wantState: SQLStateTableNotFound,
+			wantCode:  errors.StatusFailed,
+		},
+		{
+			name:      "Ignite null key",
+			err:       errors.NewError(errors.StatusFailed, "Null key is not allowed for table: PERSON"),
+			wantState: SQLStateNotNullViolation,
+			wantCode:  errors.StatusFailed,
+		},
+		{
+			name:      "status code",
+			err:       errors.NewError(errors.StatusCacheDoesNotExist, "Cache does not exist [cacheId=1]"),
+			wantState: SQLStateInvalidSchema,
+			wantCode:  errors.StatusCacheDoesNotExist,
+		},
+		{
+			name:     "unknown",
+			err:      errors.NewError(errors.StatusFailed, "Unknown error"),
+			wantCode: errors.StatusFailed,
+		},
+		{
+			name:   "not server error",
+			err:    errors.Errorf("connection lost"),
+			wantIs: true,
+		},
+		{
+			name:   "nil",
+			wantIs: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewError(tt.err)
+			if tt.wantIs {
+				if got != tt.err {
+					t.Errorf("NewError() = %v, want %v", got, tt.err)
+				}
+				return
+			}
+			e, ok := got.(*Error)
+			if !ok {
+				t.Fatalf("NewError() = %T, want *Error", got)
+			}
+			if e.SQLState != tt.wantState || e.Code != tt.wantCode {
+				t.Errorf("NewError() SQLState, Code = %q, %d, want %q, %d", e.SQLState, e.Code, tt.wantState, tt.wantCode)
+			}
+			if e.Error() != tt.err.Error() {
+				t.Errorf("Error() = %q, want %q", e.Error(), tt.err.Error())
+			}
+			if !errors.Is(e, tt.err) {
+				t.Errorf("Is(%v, %v) = false, want true", e, tt.err)
+			}
+			if NewError(e) != e {
+				t.Errorf("NewError() of Error is not the same error")
+			}
+		})
+	}
+}
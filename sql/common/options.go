@@ -0,0 +1,93 @@
+package common
+
+import (
+	"context"
+	"time"
+)
+
+// QueryOptions overrides query execution parameters of the connection for the queries executed with context.
+// Nil fields are not overridden.
+type QueryOptions struct {
+	// Query cursor page size, it is used by queries that return rows.
+	PageSize *int
+
+	// Max rows, it is used by queries that return rows.
+	MaxRows *int
+
+	// Max count of the cursor pages fetched in background, it is used by queries that return rows.
+	PrefetchPages *int
+
+	// Timeout value should be non-negative. Zero value disables timeout.
+	Timeout *time.Duration
+
+	// Distributed joins.
+	DistributedJoins *bool
+
+	// Local query.
+	LocalQuery *bool
+
+	// Replicated only - Whether query contains only replicated tables or not.
+	ReplicatedOnly *bool
+
+	// Enforce join order.
+	EnforceJoinOrder *bool
+
+	// Collocated - Whether your data is co-located or not.
+	Collocated *bool
+
+	// Lazy query execution.
+	LazyQuery *bool
+}
+
+// queryOptionsKey is context key of the query options
+type queryOptionsKey struct{}
+
+// WithQueryOptions returns copy of ctx with query options.
+// Options already set in ctx are overridden by not nil fields of opts.
+func WithQueryOptions(ctx context.Context, opts QueryOptions) context.Context {
+	if parent, ok := QueryOptionsFromContext(ctx); ok {
+		opts = parent.merge(opts)
+	}
+	return context.WithValue(ctx, queryOptionsKey{}, opts)
+}
+
+// QueryOptionsFromContext returns query options set by WithQueryOptions
+func QueryOptionsFromContext(ctx context.Context) (QueryOptions, bool) {
+	opts, ok := ctx.Value(queryOptionsKey{}).(QueryOptions)
+	return opts, ok
+}
+
+// merge returns options with fields of o overridden by not nil fields of opts
+func (o QueryOptions) merge(opts QueryOptions) QueryOptions {
+	if opts.PageSize != nil {
+		o.PageSize = opts.PageSize
+	}
+	if opts.MaxRows != nil {
+		o.MaxRows = opts.MaxRows
+	}
+	if opts.PrefetchPages != nil {
+		o.PrefetchPages = opts.PrefetchPages
+	}
+	if opts.Timeout != nil {
+		o.Timeout = opts.Timeout
+	}
+	if opts.DistributedJoins != nil {
+		o.DistributedJoins = opts.DistributedJoins
+	}
+	if opts.LocalQuery != nil {
+		o.LocalQuery = opts.LocalQuery
+	}
+	if opts.ReplicatedOnly != nil {
+		o.ReplicatedOnly = opts.ReplicatedOnly
+	}
+	if opts.EnforceJoinOrder != nil {
+		o.EnforceJoinOrder = opts.EnforceJoinOrder
+	}
+	if opts.Collocated != nil {
+		o.Collocated = opts.Collocated
+	}
+	if opts.LazyQuery != nil {
+		o.LazyQuery = opts.LazyQuery
+	}
+	return o
+}
@@ -0,0 +1,459 @@
+package ignitesql
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+	"github.com/amsokol/ignite-go-client/sql/common"
+)
+
+// Default values of the connection parameters
+const (
+	DefaultNetwork  = "tcp"
+	DefaultHost     = "127.0.0.1"
+	DefaultPort     = 10800
+	DefaultPageSize = 10000
+)
+
+// Config is configuration of the SQL driver connection.
+// Use NewConfig or ParseDSN to create it and NewConnector to open database with sql.OpenDB:
+//
+//	cfg := ignitesql.NewConfig()
+//	cfg.Host, cfg.Cache = "ignite.local", "ExampleDB"
+//	cfg.Username, cfg.Password = secrets.Username, secrets.Password
+//	cfg.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+//	connector, err := ignitesql.NewConnector(cfg)
+//	...
+//	db := sql.OpenDB(connector)
+type Config struct {
+	// Network is connection protocol, DefaultNetwork if empty
+	Network string
+	// Host is Apache Ignite Cluster host name or IP address, DefaultHost if empty
+	Host string
+	// Port is Apache Ignite Cluster port, DefaultPort if zero
+	Port int
+
+	// Cache name
+	Cache string
+
+	// Schema for the query; can be empty, in which case default PUBLIC schema will be used.
+	Schema string
+
+	// Major, Minor, Patch is binary protocol version, 1.0.0 if all are zero
+	Major, Minor, Patch int
+
+	// Username and Password are credentials of the user
+	Username, Password string
+
+	// TLS enables TLS connection with the configuration.
+	// Only InsecureSkipVerify is presented in DSN as tls-insecure-skip-verify parameter.
+	TLS *tls.Config
+
+	// TLSConfigName is name of the TLS config registered by RegisterTLSConfig, it is used instead of TLS
+	TLSConfigName string
+
+	// TLSCAFile is PEM file with CA certificates to verify server certificate
+	TLSCAFile string
+
+	// TLSCertFile and TLSKeyFile are PEM files with client certificate and its private key.
+	// If TLSKeyFile is empty, private key is loaded from TLSCertFile.
+	// Files are loaded again for new connections if they are changed (e.g. certificate is rotated).
+	TLSCertFile, TLSKeyFile string
+
+	// TLSServerName is server name to verify certificate and to send in SNI extension
+	TLSServerName string
+
+	// TLSMinVersion is minimal TLS version, e.g. tls.VersionTLS12
+	TLSMinVersion uint16
+
+	// Dialer is used to open connection (e.g. to set timeout or local address)
+	Dialer *net.Dialer
+
+	// Dial is custom function to open connection, if set, Dialer and TLS are ignored.
+	// It is not presented in DSN.
+	Dial func(network, address string) (net.Conn, error)
+
+	// PageSize is query cursor page size, DefaultPageSize if zero
+	PageSize int
+
+	// MaxRows is max rows to return by query, zero means unlimited
+	MaxRows int
+
+	// PrefetchPages is max count of the cursor pages fetched in background while application reads rows,
+	// zero disables prefetching
+	PrefetchPages int
+
+	// Timeout to execute query, precision is millisecond (rounded up). Zero value disables timeout.
+	Timeout time.Duration
+
+	// Distributed joins.
+	DistributedJoins bool
+
+	// Local query.
+	LocalQuery bool
+
+	// Replicated only - Whether query contains only replicated tables or not.
+	ReplicatedOnly bool
+
+	// Enforce join order.
+	EnforceJoinOrder bool
+
+	// Collocated - Whether your data is co-located or not.
+	Collocated bool
+
+	// Lazy query execution.
+	LazyQuery bool
+}
+
+// NewConfig creates configuration with default values
+func NewConfig() *Config {
+	return &Config{
+		Network:  DefaultNetwork,
+		Host:     DefaultHost,
+		Port:     DefaultPort,
+		Major:    1,
+		PageSize: DefaultPageSize,
+	}
+}
+
+// Clone returns copy of the configuration
+func (cfg *Config) Clone() *Config {
+	c := *cfg
+	if cfg.TLS != nil {
+		c.TLS = cfg.TLS.Clone()
+	}
+	if cfg.Dialer != nil {
+		d := *cfg.Dialer
+		c.Dialer = &d
+	}
+	return &c
+}
+
+// ParseDSN parses data source name
+// DSN format: <protocol>://<host>:<port>/<cache>?param1=<value1>&param2=<value2>&paramN=<valueN>
+//
+// DSN parts:
+// | Name                     | Mandatory | Description                                                                     | Default value                     |
+// |--------------------------|-----------|---------------------------------------------------------------------------------|-----------------------------------|
+// | protocol                 | no        | Connection protocol                                                             | tcp                               |
+// | host                     | no        | Apache Ignite Cluster host name or IP address                                   | 127.0.0.1                         |
+// | port                     | no        | Apache Ignite Cluster port                                                      | 10800                             |
+// | cache                    | yes       | Cache name                                                                      |                                   |
+//
+// DSN parameters (param1,...paramN):
+// | Name                     | Mandatory | Description                                                                     | Default value                     |
+// |--------------------------|-----------|---------------------------------------------------------------------------------|-----------------------------------|
+// | schema                   | no        | Database schema                                                                 | "" (PUBLIC schema will be used)   |
+// | version                  | no        | Binary protocol version in Semantic Version format                              | 1.0.0                             |
+// | username                 | no        | Username                                                                        | no                                |
+// | password                 | no        | Password                                                                        | no                                |
+// | tls                      | no        | Connect using TLS (yes/no or name of the config registered by RegisterTLSConfig)| no                                |
+// | tls-insecure-skip-verify | no        | Controls whether a client verifies the server's certificate chain and host name | no                                |
+// | tls-ca-file              | no        | PEM file with CA certificates to verify server certificate                      | system CA                         |
+// | tls-cert-file            | no        | PEM file with client certificate                                                | no                                |
+// | tls-key-file             | no        | PEM file with client private key                                                | tls-cert-file                     |
+// | tls-server-name          | no        | Server name to verify certificate and to send in SNI extension                  | host                              |
+// | tls-min-version          | no        | Minimal TLS version (1.0, 1.1, 1.2 or 1.3)                                      | Go default                        |
+// | page-size                | no        | Query cursor page size                                                          | 10000                             |
+// | max-rows                 | no        | Max rows to return by query                                                     | 0 (looks like it means unlimited) |
+// | prefetch-pages           | no        | Max count of the cursor pages fetched in background                             | 0 (disable prefetching)           |
+// | timeout                  | no        | Timeout in milliseconds to execute query                                        | 0 (disable timeout)               |
+// | distributed-joins        | no        | Distributed joins (yes/no)                                                      | no                                |
+// | local-query              | no        | Local query (yes/no)                                                            | no                                |
+// | replicated-only          | no        | Whether query contains only replicated tables or not (yes/no)                   | no                                |
+// | enforce-join-order       | no        | Enforce join order (yes/no)                                                     | no                                |
+// | collocated               | no        | Whether your data is co-located or not (yes/no)                                 | no                                |
+// | lazy-query               | no        | Lazy query execution (yes/no)                                                   | no                                |
+//
+// Any of tls-* parameters enables TLS, it is an error to set them together with tls=no.
+// tls-insecure-skip-verify can't be used with the registered TLS config.
+//
+// DSN example: tcp://127.0.0.1:10800/ExampleDB?version=1.1.0&page-size=100000
+func ParseDSN(dsn string) (*Config, error) {
+	cfg := NewConfig()
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse data source name")
+	}
+
+	if len(u.Scheme) > 0 {
+		cfg.Network = u.Scheme
+	}
+	if len(u.Hostname()) > 0 {
+		cfg.Host = u.Hostname()
+	}
+	if port, _ := strconv.Atoi(u.Port()); port != 0 {
+		cfg.Port = port
+	}
+
+	cfg.Cache = strings.Trim(u.Path, "/")
+
+	var tlsEnabled, tlsDisabled, tlsInsecureSkipVerify bool
+
+	for k, v := range u.Query() {
+		var val string
+		if len(v) > 0 {
+			val = strings.TrimSpace(v[0])
+		}
+		switch strings.ToLower(k) {
+		case "schema":
+			cfg.Schema = val
+		case "version":
+			if len(val) > 0 {
+				var ver *semver.Version
+				if ver, err = semver.NewVersion(val); err == nil {
+					cfg.Major, cfg.Minor, cfg.Patch = int(ver.Major()), int(ver.Minor()), int(ver.Patch())
+				}
+			}
+		case "username":
+			cfg.Username = val
+		case "password":
+			cfg.Password = val
+		case "tls":
+			if tlsEnabled, err = parseYesNo(val); err != nil {
+				if registeredTLSConfig(val) != nil {
+					cfg.TLSConfigName, err = val, nil
+				} else {
+					err = errors.Errorf("expected \"yes\", \"no\" or name of the registered TLS config: %s", val)
+				}
+			} else {
+				tlsDisabled = !tlsEnabled
+			}
+		case "tls-insecure-skip-verify":
+			tlsInsecureSkipVerify, err = parseYesNo(val)
+		case "tls-ca-file":
+			cfg.TLSCAFile = val
+		case "tls-cert-file":
+			cfg.TLSCertFile = val
+		case "tls-key-file":
+			cfg.TLSKeyFile = val
+		case "tls-server-name":
+			cfg.TLSServerName = val
+		case "tls-min-version":
+			if len(val) > 0 {
+				cfg.TLSMinVersion, err = parseTLSVersion(val)
+			}
+		case "page-size":
+			if len(val) > 0 {
+				cfg.PageSize, err = strconv.Atoi(val)
+			}
+		case "max-rows":
+			if len(val) > 0 {
+				cfg.MaxRows, err = strconv.Atoi(val)
+			}
+		case "prefetch-pages":
+			if len(val) > 0 {
+				if cfg.PrefetchPages, err = strconv.Atoi(val); err == nil && cfg.PrefetchPages < 0 {
+					err = errors.Errorf("negative count of pages")
+				}
+			}
+		case "timeout":
+			if len(val) > 0 {
+				var ms int64
+				if ms, err = strconv.ParseInt(val, 0, 64); err == nil {
+					cfg.Timeout = time.Duration(ms) * time.Millisecond
+				}
+			}
+		case "distributed-joins":
+			cfg.DistributedJoins, err = parseYesNo(val)
+		case "local-query":
+			cfg.LocalQuery, err = parseYesNo(val)
+		case "replicated-only":
+			cfg.ReplicatedOnly, err = parseYesNo(val)
+		case "enforce-join-order":
+			cfg.EnforceJoinOrder, err = parseYesNo(val)
+		case "collocated":
+			cfg.Collocated, err = parseYesNo(val)
+		case "lazy-query":
+			cfg.LazyQuery, err = parseYesNo(val)
+		default:
+			return nil, errors.Errorf("unknown connection parameter \"%s\" with value \"%v\"", k, v)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "unexpected parameter \"%s\" with value \"%s\"", k, val)
+		}
+	}
+
+	switch {
+	case tlsDisabled:
+		if tlsInsecureSkipVerify || cfg.tlsEnabled() {
+			return nil, errors.Errorf("TLS parameters are set, but TLS is disabled by \"tls=no\"")
+		}
+	case len(cfg.TLSConfigName) > 0:
+		if tlsInsecureSkipVerify {
+			return nil, errors.Errorf("\"tls-insecure-skip-verify\" can't be used with the registered TLS config \"%s\"",
+				cfg.TLSConfigName)
+		}
+	case tlsEnabled || tlsInsecureSkipVerify:
+		cfg.TLS = &tls.Config{InsecureSkipVerify: tlsInsecureSkipVerify}
+	}
+
+	return cfg, nil
+}
+
+// FormatDSN formats configuration as data source name accepted by ParseDSN.
+// Parameters with default values are omitted.
+func (cfg *Config) FormatDSN() string {
+	network, host, port := cfg.Network, cfg.Host, cfg.Port
+	if len(network) == 0 {
+		network = DefaultNetwork
+	}
+	if len(host) == 0 {
+		host = DefaultHost
+	}
+	if port == 0 {
+		port = DefaultPort
+	}
+	u := url.URL{
+		Scheme: network,
+		Host:   net.JoinHostPort(host, strconv.Itoa(port)),
+		Path:   "/" + cfg.Cache,
+	}
+
+	q := url.Values{}
+	if len(cfg.Schema) > 0 {
+		q.Set("schema", cfg.Schema)
+	}
+	if major, minor, patch := cfg.version(); major != 1 || minor != 0 || patch != 0 {
+		q.Set("version", fmt.Sprintf("%d.%d.%d", major, minor, patch))
+	}
+	if len(cfg.Username) > 0 {
+		q.Set("username", cfg.Username)
+	}
+	if len(cfg.Password) > 0 {
+		q.Set("password", cfg.Password)
+	}
+	switch {
+	case len(cfg.TLSConfigName) > 0:
+		q.Set("tls", cfg.TLSConfigName)
+	case cfg.TLS != nil:
+		q.Set("tls", "yes")
+		if cfg.TLS.InsecureSkipVerify {
+			q.Set("tls-insecure-skip-verify", "yes")
+		}
+	}
+	for _, p := range []struct {
+		name  string
+		value string
+	}{
+		{"tls-ca-file", cfg.TLSCAFile},
+		{"tls-cert-file", cfg.TLSCertFile},
+		{"tls-key-file", cfg.TLSKeyFile},
+		{"tls-server-name", cfg.TLSServerName},
+		{"tls-min-version", tlsVersions[cfg.TLSMinVersion]},
+	} {
+		if len(p.value) > 0 {
+			q.Set(p.name, p.value)
+		}
+	}
+	if cfg.PageSize != 0 && cfg.PageSize != DefaultPageSize {
+		q.Set("page-size", strconv.Itoa(cfg.PageSize))
+	}
+	if cfg.MaxRows != 0 {
+		q.Set("max-rows", strconv.Itoa(cfg.MaxRows))
+	}
+	if cfg.PrefetchPages != 0 {
+		q.Set("prefetch-pages", strconv.Itoa(cfg.PrefetchPages))
+	}
+	if cfg.Timeout != 0 {
+		q.Set("timeout", strconv.FormatInt(timeoutMillis(cfg.Timeout), 10))
+	}
+	for _, p := range []struct {
+		name  string
+		value bool
+	}{
+		{"distributed-joins", cfg.DistributedJoins},
+		{"local-query", cfg.LocalQuery},
+		{"replicated-only", cfg.ReplicatedOnly},
+		{"enforce-join-order", cfg.EnforceJoinOrder},
+		{"collocated", cfg.Collocated},
+		{"lazy-query", cfg.LazyQuery},
+	} {
+		if p.value {
+			q.Set(p.name, "yes")
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// version returns protocol version, 1.0.0 if it is not set
+func (cfg *Config) version() (int, int, int) {
+	if cfg.Major == 0 && cfg.Minor == 0 && cfg.Patch == 0 {
+		return 1, 0, 0
+	}
+	return cfg.Major, cfg.Minor, cfg.Patch
+}
+
+// connInfo returns connection info with default values for the empty fields
+func (cfg *Config) connInfo() (common.ConnInfo, error) {
+	var ci common.ConnInfo
+	var err error
+
+	if ci.Network = cfg.Network; len(ci.Network) == 0 {
+		ci.Network = DefaultNetwork
+	}
+	if ci.Host = cfg.Host; len(ci.Host) == 0 {
+		ci.Host = DefaultHost
+	}
+	if ci.Port = cfg.Port; ci.Port == 0 {
+		ci.Port = DefaultPort
+	}
+	ci.Major, ci.Minor, ci.Patch = cfg.version()
+	ci.Username, ci.Password = cfg.Username, cfg.Password
+	if ci.TLSConfig, err = cfg.tlsConfig(); err != nil {
+		return ci, err
+	}
+	if cfg.Dialer != nil {
+		ci.Dialer = *cfg.Dialer
+	}
+	ci.Dial = cfg.Dial
+
+	ci.Cache = cfg.Cache
+	ci.Schema = cfg.Schema
+	if ci.PageSize = cfg.PageSize; ci.PageSize == 0 {
+		ci.PageSize = DefaultPageSize
+	}
+	ci.MaxRows = cfg.MaxRows
+	ci.PrefetchPages = cfg.PrefetchPages
+	ci.Timeout = timeoutMillis(cfg.Timeout)
+	ci.DistributedJoins = cfg.DistributedJoins
+	ci.LocalQuery = cfg.LocalQuery
+	ci.ReplicatedOnly = cfg.ReplicatedOnly
+	ci.EnforceJoinOrder = cfg.EnforceJoinOrder
+	ci.Collocated = cfg.Collocated
+	ci.LazyQuery = cfg.LazyQuery
+
+	return ci, nil
+}
+
+// parseYesNo parses boolean value (yes/no)
+func parseYesNo(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "yes":
+		return true, nil
+	case "no":
+		return false, nil
+	default:
+		return false, errors.Errorf("invalid boolean value (expected \"yes\" or \"no\"): %s", s)
+	}
+}
+
+// timeoutMillis returns timeout in milliseconds rounded up,
+// so sub-millisecond timeout is not turned into zero that disables timeout
+func timeoutMillis(d time.Duration) int64 {
+	if d <= 0 {
+		return int64(d / time.Millisecond)
+	}
+	return int64((d + time.Millisecond - 1) / time.Millisecond)
+}
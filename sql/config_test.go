@@ -0,0 +1,156 @@
+package ignitesql
+
+import (
+	"crypto/tls"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		dsn     string
+		want    *Config
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			dsn:  "/ExampleDB",
+			want: &Config{Network: "tcp", Host: "127.0.0.1", Port: 10800, Cache: "ExampleDB", Major: 1, PageSize: 10000},
+		},
+		{
+			name: "all parameters",
+			dsn: "tcp://localhost:10801/TestDB?schema=SCHEMA&version=1.1.1&username=ignite&password=p%40ss%26word" +
+				"&tls=yes&tls-insecure-skip-verify=yes&page-size=100&max-rows=99&prefetch-pages=2&timeout=5555" +
+				"&distributed-joins=yes&local-query=yes&replicated-only=yes&enforce-join-order=yes&collocated=yes&lazy-query=yes",
+			want: &Config{Network: "tcp", Host: "localhost", Port: 10801, Cache: "TestDB", Schema: "SCHEMA",
+				Major: 1, Minor: 1, Patch: 1, Username: "ignite", Password: "p@ss&word",
+				TLS: &tls.Config{InsecureSkipVerify: true}, PageSize: 100, MaxRows: 99, PrefetchPages: 2, Timeout: 5555 * time.Millisecond,
+				DistributedJoins: true, LocalQuery: true, ReplicatedOnly: true, EnforceJoinOrder: true, Collocated: true, LazyQuery: true},
+		},
+		{
+			name:    "negative prefetch pages",
+			dsn:     "tcp://localhost:10800/TestDB?prefetch-pages=-1",
+			wantErr: true,
+		},
+		{
+			name:    "unknown parameter",
+			dsn:     "tcp://localhost:10800/TestDB?unknown=1",
+			wantErr: true,
+		},
+		{
+			name:    "invalid boolean",
+			dsn:     "tcp://localhost:10800/TestDB?tls=true",
+			wantErr: true,
+		},
+		{
+			name: "insecure skip verify enables TLS",
+			dsn:  "tcp://localhost:10800/TestDB?tls-insecure-skip-verify=yes",
+			want: &Config{Network: "tcp", Host: "localhost", Port: 10800, Cache: "TestDB", Major: 1, PageSize: 10000,
+				TLS: &tls.Config{InsecureSkipVerify: true}},
+		},
+		{
+			name:    "TLS disabled with CA file",
+			dsn:     "tcp://localhost:10800/TestDB?tls=no&tls-ca-file=ca.pem",
+			wantErr: true,
+		},
+		{
+			name:    "TLS disabled with insecure skip verify",
+			dsn:     "tcp://localhost:10800/TestDB?tls=no&tls-insecure-skip-verify=yes",
+			wantErr: true,
+		},
+		{
+			name: "TLS disabled",
+			dsn:  "tcp://localhost:10800/TestDB?tls=no&tls-insecure-skip-verify=no",
+			want: &Config{Network: "tcp", Host: "localhost", Port: 10800, Cache: "TestDB", Major: 1, PageSize: 10000},
+		},
+		{
+			name:    "invalid version",
+			dsn:     "tcp://localhost:10800/TestDB?version=one",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDSN(tt.dsn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDSN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDSN() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_FormatDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+		want string
+	}{
+		{
+			name: "defaults",
+			cfg:  NewConfig(),
+			want: "tcp://127.0.0.1:10800/",
+		},
+		{
+			name: "zero config",
+			cfg:  &Config{Cache: "ExampleDB"},
+			want: "tcp://127.0.0.1:10800/ExampleDB",
+		},
+		{
+			name: "all parameters",
+			cfg: &Config{Network: "tcp", Host: "::1", Port: 10801, Cache: "TestDB", Schema: "SCHEMA",
+				Major: 1, Minor: 1, Patch: 1, Username: "ignite", Password: "p@ss&word",
+				TLS: &tls.Config{InsecureSkipVerify: true}, PageSize: 100, MaxRows: 99, PrefetchPages: 2, Timeout: 5555 * time.Millisecond,
+				DistributedJoins: true, LocalQuery: true, ReplicatedOnly: true, EnforceJoinOrder: true, Collocated: true, LazyQuery: true},
+			want: "tcp://[::1]:10801/TestDB?collocated=yes&distributed-joins=yes&enforce-join-order=yes&lazy-query=yes" +
+				"&local-query=yes&max-rows=99&page-size=100&password=p%40ss%26word&prefetch-pages=2&replicated-only=yes&schema=SCHEMA" +
+				"&timeout=5555&tls=yes&tls-insecure-skip-verify=yes&username=ignite&version=1.1.1",
+		},
+		{
+			name: "sub-millisecond timeout",
+			cfg:  &Config{Cache: "TestDB", Timeout: 1500 * time.Microsecond},
+			want: "tcp://127.0.0.1:10800/TestDB?timeout=2",
+		},
+		{
+			name: "TLS files",
+			cfg: &Config{Cache: "TestDB", TLSCAFile: testCAFile, TLSCertFile: testClientFile, TLSKeyFile: testClientFile,
+				TLSServerName: "ignite.local", TLSMinVersion: tls.VersionTLS12},
+			want: "tcp://127.0.0.1:10800/TestDB?tls-ca-file=..%2Ftestdata%2Fssl%2Fca.pem" +
+				"&tls-cert-file=..%2Ftestdata%2Fssl%2Fclient_full.pem&tls-key-file=..%2Ftestdata%2Fssl%2Fclient_full.pem" +
+				"&tls-min-version=1.2&tls-server-name=ignite.local",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.cfg.FormatDSN()
+			if got != tt.want {
+				t.Errorf("FormatDSN() = %v, want %v", got, tt.want)
+			}
+			// every parameter must survive round trip
+			cfg, err := ParseDSN(got)
+			if err != nil {
+				t.Fatalf("ParseDSN() error = %v", err)
+			}
+			if dsn := cfg.FormatDSN(); dsn != got {
+				t.Errorf("FormatDSN() after ParseDSN() = %v, want %v", dsn, got)
+			}
+			got1, err := cfg.connInfo()
+			if err != nil {
+				t.Fatalf("connInfo() error = %v", err)
+			}
+			want1, err := tt.cfg.connInfo()
+			if err != nil {
+				t.Fatalf("connInfo() error = %v", err)
+			}
+			// TLS config is checked by DSN
+			got1.TLSConfig, want1.TLSConfig = nil, nil
+			if !reflect.DeepEqual(got1, want1) {
+				t.Errorf("ParseDSN() = %#v, want %#v", got1, want1)
+			}
+		})
+	}
+}
@@ -7,12 +7,28 @@ import (
 	"database/sql/driver"
 
 	"github.com/amsokol/ignite-go-client/binary/errors"
-	"github.com/amsokol/ignite-go-client/sql/common"
-	"github.com/amsokol/ignite-go-client/sql/v1"
 )
 
+// NewConnector returns connector for sql.OpenDB.
+// Configuration is copied, so it may be changed after the call.
+func NewConnector(cfg *Config) (driver.Connector, error) {
+	c := cfg.Clone()
+	if major, minor, patch := c.version(); major != 1 {
+		return nil, errors.Errorf("unsupported protocol version: v%d.%d.%d", major, minor, patch)
+	}
+	if _, err := c.tlsConfig(); err != nil {
+		return nil, errors.Wrapf(err, "invalid TLS configuration")
+	}
+	// URL is used in debug messages, so it must not contain password
+	dsn := c.Clone()
+	dsn.Password = ""
+	return &connector{config: c, url: dsn.FormatDSN()}, nil
+}
+
 type connector struct {
-	info common.ConnInfo
+	config *Config
+	// url is connection URL for debug messages
+	url string
 
 	driver.Connector
 }
@@ -29,12 +45,13 @@ type connector struct {
 // The returned connection is only used by one goroutine at a
 // time.
 func (c *connector) Connect(context.Context) (driver.Conn, error) {
-	switch c.info.Major {
-	case 1:
-		return v1.Connect(c.info)
-	default:
-		return nil, errors.Errorf("unsupported protocol version: v%d.%d.%d", c.info.Major, c.info.Minor, c.info.Patch)
+	// connection info is created for every connection to load rotated TLS files
+	ci, err := c.config.connInfo()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create connection info")
 	}
+	ci.URL = c.url
+	return connect(ci)
 }
 
 // Driver returns the underlying Driver of the Connector,
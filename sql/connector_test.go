@@ -4,8 +4,12 @@ package ignitesql
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
+	"net"
 	"testing"
+
+	"github.com/amsokol/ignite-go-client/ignitetest"
 )
 
 func Test_connector_Connect(t *testing.T) {
@@ -48,3 +52,46 @@ func Test_connector_Connect(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConnector(t *testing.T) {
+	srv, err := ignitetest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	defer srv.Close()
+	var schema string
+	srv.SQL = func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		schema = q.Schema
+		return &ignitetest.SQLResult{Columns: []string{"NAME"}, Rows: [][]interface{}{{"a"}}}, nil
+	}
+
+	var dials int
+	cfg := NewConfig()
+	cfg.Port = srv.Addr().Port
+	cfg.Schema = "TEST"
+	cfg.Dial = func(network, address string) (net.Conn, error) {
+		dials++
+		return net.Dial(network, address)
+	}
+	c, err := NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("NewConnector() error = %v", err)
+	}
+	// connector must not depend on changes of configuration
+	cfg.Schema = "CHANGED"
+
+	db := sql.OpenDB(c)
+	defer db.Close()
+	var name string
+	if err = db.QueryRow("SELECT NAME FROM T").Scan(&name); err != nil {
+		t.Fatalf("QueryRow() error = %v", err)
+	}
+	if name != "a" || schema != "TEST" || dials != 1 {
+		t.Errorf("QueryRow() = %q, schema = %q, dials = %d, want \"a\", \"TEST\", 1", name, schema, dials)
+	}
+
+	cfg.Major = 2
+	if _, err = NewConnector(cfg); err == nil {
+		t.Errorf("NewConnector() with unsupported protocol version error = nil")
+	}
+}
@@ -11,9 +11,9 @@ import (
 // OpenConnector must parse the name in the same format that Driver.
 // Open parses the name parameter.
 func (d *Driver) OpenConnector(name string) (driver.Connector, error) {
-	ci, err := d.parseURL(name)
+	cfg, err := ParseDSN(name)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse connection name")
 	}
-	return &connector{info: ci}, nil
+	return &connector{config: cfg, url: name}, nil
 }
@@ -0,0 +1,52 @@
+package ignitesql
+
+import (
+	"github.com/amsokol/ignite-go-client/binary/errors"
+	"github.com/amsokol/ignite-go-client/sql/common"
+)
+
+// Error is error returned by server for SQL query.
+// SQLSTATE code is detected by H2 error code embedded into message (e.g. "[42102-197]"),
+// Apache Ignite error message (e.g. "Duplicate key during INSERT") and status code:
+//
+//	_, err := db.Exec("INSERT INTO Person(ID, NAME) VALUES(?, ?)", int64(1), "Alice")
+//	var e *ignitesql.Error
+//	if errors.As(err, &e) {
+//		log.Printf("SQLSTATE %s, status %d: %s", e.SQLState, e.Code, e.Message)
+//	}
+type Error = common.Error
+
+// IsDuplicateKey returns true if row with the same key already exists
+func IsDuplicateKey(err error) bool {
+	return hasSQLState(err, common.SQLStateDuplicateKey)
+}
+
+// IsTableNotFound returns true if table or view does not exist
+func IsTableNotFound(err error) bool {
+	return hasSQLState(err, common.SQLStateTableNotFound)
+}
+
+// IsSyntaxError returns true if query can't be parsed
+func IsSyntaxError(err error) bool {
+	// H2 reports syntax errors as 42000 and 42001
+	return hasSQLState(err, common.SQLStateSyntaxError, "42001")
+}
+
+// IsNotNullViolation returns true if null value is set to not null column or key
+func IsNotNullViolation(err error) bool {
+	return hasSQLState(err, common.SQLStateNotNullViolation)
+}
+
+// hasSQLState returns true if err is Error with any of SQLSTATE codes
+func hasSQLState(err error, states ...string) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	for _, s := range states {
+		if e.SQLState == s {
+			return true
+		}
+	}
+	return false
+}
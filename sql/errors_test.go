@@ -0,0 +1,35 @@
+package ignitesql
+
+import (
+	"testing"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+	"github.com/amsokol/ignite-go-client/sql/common"
+)
+
+func TestIsError(t *testing.T) {
+	serverError := func(message string) error {
+		return errors.Wrapf(common.NewError(errors.NewError(errors.StatusFailed, message)), "query failed")
+	}
+	tests := []struct {
+		name string
+		err  error
+		is   func(err error) bool
+		want bool
+	}{
+		{"duplicate key", serverError("Duplicate key during INSERT [key=1]"), IsDuplicateKey, true},
+		{"not duplicate key", serverError("Table doesn't exist: PERSON"), IsDuplicateKey, false},
+		{"table not found", serverError("Table \"PERSON\" not found; SQL statement:\nSELECT * FROM PERSON [42102-197]"), IsTableNotFound, true},
+		{"syntax error", serverError("Syntax error in SQL statement \"SELEC[*] 1 \"; expected \"SELECT\"; SQL statement:\nSELEC 1 [42001-197]"), IsSyntaxError, true},
+		{"not null violation", serverError("Null value is not allowed for column 'NAME'"), IsNotNullViolation, true},
+		{"not server error", errors.Errorf("Duplicate key"), IsDuplicateKey, false},
+		{"nil", nil, IsTableNotFound, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.is(tt.err); got != tt.want {
+				t.Errorf("Is...(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package ignitesql
+
+import (
+	"context"
+	"time"
+
+	"github.com/amsokol/ignite-go-client/sql/common"
+)
+
+// QueryOptions overrides query execution parameters set by DSN
+// (page-size, max-rows, prefetch-pages, timeout, distributed-joins, local-query, replicated-only,
+// enforce-join-order, collocated and lazy-query) for the queries executed with context.
+// Nil fields are not overridden.
+type QueryOptions = common.QueryOptions
+
+// WithQueryOptions returns copy of ctx with query options, which are used by
+// QueryContext, ExecContext and their statement variants instead of the DSN parameters:
+//
+//	ctx := ignitesql.WithQueryOptions(ctx, ignitesql.QueryOptions{
+//		DistributedJoins: ignitesql.Bool(true),
+//		PageSize:         ignitesql.Int(100000),
+//		Timeout:          ignitesql.Duration(5 * time.Minute),
+//	})
+//	rows, err := db.QueryContext(ctx, "SELECT ...")
+//
+// Options already set in ctx are overridden by not nil fields of opts.
+// Page size, max rows and prefetch pages are used by queries that return rows only.
+func WithQueryOptions(ctx context.Context, opts QueryOptions) context.Context {
+	return common.WithQueryOptions(ctx, opts)
+}
+
+// Bool returns pointer to v
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Int returns pointer to v
+func Int(v int) *int {
+	return &v
+}
+
+// Duration returns pointer to v
+func Duration(v time.Duration) *time.Duration {
+	return &v
+}
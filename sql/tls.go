@@ -0,0 +1,262 @@
+package ignitesql
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+)
+
+// tlsConfigs is registry of the named TLS configs
+var tlsConfigs = struct {
+	sync.RWMutex
+	m map[string]*tls.Config
+}{m: map[string]*tls.Config{}}
+
+// RegisterTLSConfig registers TLS config with the name, so it can be used in DSN as tls=<name>:
+//
+//	rootCertPool := x509.NewCertPool()
+//	rootCertPool.AppendCertsFromPEM(pem)
+//	ignitesql.RegisterTLSConfig("custom", &tls.Config{RootCAs: rootCertPool, Certificates: certs})
+//	db, err := sql.Open("ignite", "tcp://ignite.local:10800/ExampleDB?tls=custom")
+//
+// Config is copied, names "yes" and "no" are reserved.
+func RegisterTLSConfig(name string, config *tls.Config) error {
+	if _, err := parseYesNo(name); err == nil || len(name) == 0 {
+		return errors.Errorf("TLS config name \"%s\" is reserved", name)
+	}
+
+	tlsConfigs.Lock()
+	defer tlsConfigs.Unlock()
+
+	tlsConfigs.m[name] = config.Clone()
+	return nil
+}
+
+// DeregisterTLSConfig removes TLS config registered with the name
+func DeregisterTLSConfig(name string) {
+	tlsConfigs.Lock()
+	defer tlsConfigs.Unlock()
+
+	delete(tlsConfigs.m, name)
+}
+
+// registeredTLSConfig returns copy of the TLS config registered with the name or nil
+func registeredTLSConfig(name string) *tls.Config {
+	tlsConfigs.RLock()
+	defer tlsConfigs.RUnlock()
+
+	if c, ok := tlsConfigs.m[name]; ok {
+		return c.Clone()
+	}
+	return nil
+}
+
+// tlsVersions maps TLS versions to DSN values
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "1.0",
+	tls.VersionTLS11: "1.1",
+	tls.VersionTLS12: "1.2",
+	tls.VersionTLS13: "1.3",
+}
+
+// parseTLSVersion parses TLS version (1.0, 1.1, 1.2 or 1.3)
+func parseTLSVersion(s string) (uint16, error) {
+	for v, name := range tlsVersions {
+		if strings.TrimPrefix(strings.ToLower(s), "tls") == name {
+			return v, nil
+		}
+	}
+	return 0, errors.Errorf("invalid TLS version (expected 1.0, 1.1, 1.2 or 1.3): %s", s)
+}
+
+// tlsEnabled returns true if any of TLS parameters is set
+func (cfg *Config) tlsEnabled() bool {
+	return cfg.TLS != nil || len(cfg.TLSConfigName) > 0 ||
+		len(cfg.TLSCAFile) > 0 || len(cfg.TLSCertFile) > 0 || len(cfg.TLSKeyFile) > 0 ||
+		len(cfg.TLSServerName) > 0 || cfg.TLSMinVersion != 0
+}
+
+// tlsConfig returns TLS config to open connection or nil if TLS is disabled.
+// CA, certificate and key files are loaded again if they are changed since the last call,
+// client certificate is loaded for every TLS handshake.
+func (cfg *Config) tlsConfig() (*tls.Config, error) {
+	if !cfg.tlsEnabled() {
+		return nil, nil
+	}
+
+	var c *tls.Config
+	switch {
+	case len(cfg.TLSConfigName) > 0:
+		if c = registeredTLSConfig(cfg.TLSConfigName); c == nil {
+			return nil, errors.Errorf("TLS config \"%s\" is not registered", cfg.TLSConfigName)
+		}
+	case cfg.TLS != nil:
+		c = cfg.TLS.Clone()
+	default:
+		c = &tls.Config{}
+	}
+
+	if len(cfg.TLSServerName) > 0 {
+		c.ServerName = cfg.TLSServerName
+	}
+	if cfg.TLSMinVersion != 0 {
+		c.MinVersion = cfg.TLSMinVersion
+	}
+
+	if len(cfg.TLSCAFile) > 0 {
+		pool, err := loadCertPool(cfg.TLSCAFile)
+		if err != nil {
+			return nil, err
+		}
+		c.RootCAs = pool
+	}
+
+	switch {
+	case len(cfg.TLSCertFile) > 0:
+		certFile, keyFile := cfg.TLSCertFile, cfg.TLSKeyFile
+		if len(keyFile) == 0 {
+			// key is in the certificate file
+			keyFile = certFile
+		}
+		// fail fast if files are invalid
+		if _, err := loadKeyPair(certFile, keyFile); err != nil {
+			return nil, err
+		}
+		c.Certificates = nil
+		c.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return loadKeyPair(certFile, keyFile)
+		}
+	case len(cfg.TLSKeyFile) > 0:
+		return nil, errors.Errorf("TLS key file \"%s\" is set without certificate file", cfg.TLSKeyFile)
+	}
+
+	return c, nil
+}
+
+// loadCertPool loads PEM encoded CA certificates.
+// OpenSSL "TRUSTED CERTIFICATE" blocks are supported, trust settings are ignored.
+func loadCertPool(file string) (*x509.CertPool, error) {
+	v, err := loadTLSFiles([]string{file}, func(data [][]byte) (interface{}, error) {
+		pool := x509.NewCertPool()
+		var count int
+		for rest := data[0]; ; {
+			var block *pem.Block
+			if block, rest = pem.Decode(rest); block == nil {
+				break
+			}
+			der := block.Bytes
+			switch block.Type {
+			case "CERTIFICATE":
+			case "TRUSTED CERTIFICATE":
+				// certificate is followed by trust settings
+				var raw asn1.RawValue
+				if _, err := asn1.Unmarshal(der, &raw); err != nil {
+					return nil, errors.Wrapf(err, "failed to parse trusted certificate")
+				}
+				der = raw.FullBytes
+			default:
+				continue
+			}
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse certificate")
+			}
+			pool.AddCert(cert)
+			count++
+		}
+		if count == 0 {
+			return nil, errors.Errorf("no certificates found")
+		}
+		return pool, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load TLS CA file \"%s\"", file)
+	}
+	return v.(*x509.CertPool), nil
+}
+
+// loadKeyPair loads PEM encoded certificate chain and private key
+func loadKeyPair(certFile, keyFile string) (*tls.Certificate, error) {
+	v, err := loadTLSFiles([]string{certFile, keyFile}, func(data [][]byte) (interface{}, error) {
+		cert, err := tls.X509KeyPair(data[0], data[1])
+		if err != nil {
+			return nil, err
+		}
+		return &cert, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load TLS certificate file \"%s\" and key file \"%s\"", certFile, keyFile)
+	}
+	return v.(*tls.Certificate), nil
+}
+
+// tlsFile is state of the file when it was loaded
+type tlsFile struct {
+	modTime time.Time
+	size    int64
+}
+
+// tlsFileCache is parsed content of the files
+type tlsFileCache struct {
+	files []tlsFile
+	value interface{}
+}
+
+// tlsFiles caches parsed TLS files by file names
+var tlsFiles = struct {
+	sync.Mutex
+	m map[string]tlsFileCache
+}{m: map[string]tlsFileCache{}}
+
+// loadTLSFiles returns parsed content of the files.
+// Files are read and parsed again if modification time or size of any of them is changed (e.g. certificate is rotated).
+func loadTLSFiles(names []string, parse func(data [][]byte) (interface{}, error)) (interface{}, error) {
+	files := make([]tlsFile, 0, len(names))
+	for _, name := range names {
+		fi, err := os.Stat(name)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, tlsFile{modTime: fi.ModTime(), size: fi.Size()})
+	}
+	key := strings.Join(names, "\x00")
+
+	tlsFiles.Lock()
+	defer tlsFiles.Unlock()
+
+	if c, ok := tlsFiles.m[key]; ok && sameTLSFiles(c.files, files) {
+		return c.value, nil
+	}
+	data := make([][]byte, 0, len(names))
+	for _, name := range names {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b)
+	}
+	v, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	tlsFiles.m[key] = tlsFileCache{files: files, value: v}
+	return v, nil
+}
+
+// sameTLSFiles returns true if files are not changed
+func sameTLSFiles(a, b []tlsFile) bool {
+	for i := range a {
+		if !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+	return true
+}
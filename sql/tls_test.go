@@ -0,0 +1,189 @@
+package ignitesql
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	testCAFile         = "../testdata/ssl/ca.pem"
+	testClientFile     = "../testdata/ssl/client_full.pem"
+	testClientFile2    = "../testdata/ssl/client_unknown.pem"
+	testClientSubject  = "ODBC"
+	testClientSubject2 = "Unknown"
+)
+
+func TestRegisterTLSConfig(t *testing.T) {
+	for _, name := range []string{"", "yes", "NO"} {
+		if err := RegisterTLSConfig(name, &tls.Config{}); err == nil {
+			t.Errorf("RegisterTLSConfig(%q) error = nil, want reserved name error", name)
+		}
+	}
+
+	if err := RegisterTLSConfig("custom", &tls.Config{ServerName: "ignite.local"}); err != nil {
+		t.Fatalf("RegisterTLSConfig() error = %v", err)
+	}
+	defer DeregisterTLSConfig("custom")
+
+	cfg, err := ParseDSN("tcp://localhost:10800/TestDB?tls=custom&tls-min-version=1.2")
+	if err != nil {
+		t.Fatalf("ParseDSN() error = %v", err)
+	}
+	if cfg.TLSConfigName != "custom" || cfg.TLSMinVersion != tls.VersionTLS12 {
+		t.Errorf("ParseDSN() TLSConfigName = %q, TLSMinVersion = %x", cfg.TLSConfigName, cfg.TLSMinVersion)
+	}
+	if dsn, want := cfg.FormatDSN(), "tcp://localhost:10800/TestDB?tls=custom&tls-min-version=1.2"; dsn != want {
+		t.Errorf("FormatDSN() = %v, want %v", dsn, want)
+	}
+	c, err := cfg.tlsConfig()
+	if err != nil {
+		t.Fatalf("tlsConfig() error = %v", err)
+	}
+	if c.ServerName != "ignite.local" || c.MinVersion != tls.VersionTLS12 {
+		t.Errorf("tlsConfig() ServerName = %q, MinVersion = %x", c.ServerName, c.MinVersion)
+	}
+	// registered config must not be changed
+	if registeredTLSConfig("custom").MinVersion != 0 {
+		t.Errorf("registered TLS config is changed")
+	}
+
+	if _, err = ParseDSN("tcp://localhost:10800/TestDB?tls=unknown"); err == nil {
+		t.Errorf("ParseDSN() with unknown TLS config error = nil")
+	}
+	if _, err = ParseDSN("tcp://localhost:10800/TestDB?tls=custom&tls-insecure-skip-verify=yes"); err == nil {
+		t.Errorf("ParseDSN() with registered TLS config and tls-insecure-skip-verify error = nil")
+	}
+	DeregisterTLSConfig("custom")
+	if _, err = cfg.tlsConfig(); err == nil {
+		t.Errorf("tlsConfig() with deregistered TLS config error = nil")
+	}
+}
+
+func TestConfig_tlsConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "disabled", wantNil: true},
+		{name: "CA file", cfg: Config{TLSCAFile: testCAFile}},
+		{name: "certificate and key files", cfg: Config{TLSCertFile: testClientFile, TLSKeyFile: testClientFile}},
+		{name: "certificate file with key", cfg: Config{TLSCertFile: testClientFile}},
+		{name: "server name", cfg: Config{TLSServerName: "ignite.local"}},
+		{name: "missing CA file", cfg: Config{TLSCAFile: "missing.pem"}, wantErr: true},
+		{name: "invalid CA file", cfg: Config{TLSCAFile: "../testdata/configuration-for-tests.xml"}, wantErr: true},
+		{name: "key file without certificate file", cfg: Config{TLSKeyFile: testClientFile}, wantErr: true},
+		{name: "certificate file without key", cfg: Config{TLSCertFile: testCAFile}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.tlsConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tlsConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got == nil) != tt.wantNil {
+				t.Errorf("tlsConfig() = %v, wantNil %v", got, tt.wantNil)
+			}
+			if got == nil {
+				return
+			}
+			if len(tt.cfg.TLSCAFile) > 0 && got.RootCAs == nil {
+				t.Errorf("tlsConfig() RootCAs is not loaded")
+			}
+			if len(tt.cfg.TLSCertFile) > 0 && got.GetClientCertificate == nil {
+				t.Errorf("tlsConfig() GetClientCertificate is not set")
+			}
+			if got.ServerName != tt.cfg.TLSServerName {
+				t.Errorf("tlsConfig() ServerName = %q, want %q", got.ServerName, tt.cfg.TLSServerName)
+			}
+		})
+	}
+}
+
+// tlsHandshake makes TLS handshake and returns common name of the client certificate and SNI received by server
+func tlsHandshake(t *testing.T, client *tls.Config, server *tls.Config) (string, string, error) {
+	cc, sc := net.Pipe()
+	defer cc.Close()
+	defer sc.Close()
+
+	var serverName string
+	server = server.Clone()
+	server.ClientAuth = tls.RequireAnyClientCert
+	server.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		serverName = hello.ServerName
+		return nil, nil
+	}
+	s := tls.Server(sc, server)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Handshake()
+	}()
+	err := tls.Client(cc, client).Handshake()
+	if err != nil {
+		sc.Close()
+		<-done
+		return "", "", err
+	}
+	if err = <-done; err != nil {
+		return "", "", err
+	}
+	return s.ConnectionState().PeerCertificates[0].Subject.CommonName, serverName, nil
+}
+
+func Test_tlsConfig_handshake(t *testing.T) {
+	serverCert, err := tls.LoadX509KeyPair(testClientFile2, testClientFile2)
+	if err != nil {
+		t.Fatalf("failed to load server certificate: %v", err)
+	}
+	server := &tls.Config{Certificates: []tls.Certificate{serverCert}}
+
+	// certificate is rotated by replacing file content
+	certFile := filepath.Join(t.TempDir(), "client.pem")
+	copyFile(t, testClientFile, certFile)
+
+	cfg, err := ParseDSN("tcp://localhost:10800/TestDB?tls=yes&tls-insecure-skip-verify=yes" +
+		"&tls-cert-file=" + certFile + "&tls-server-name=ignite.local&tls-min-version=1.2")
+	if err != nil {
+		t.Fatalf("ParseDSN() error = %v", err)
+	}
+	client, err := cfg.tlsConfig()
+	if err != nil {
+		t.Fatalf("tlsConfig() error = %v", err)
+	}
+	subject, serverName, err := tlsHandshake(t, client, server)
+	if err != nil {
+		t.Fatalf("handshake error = %v", err)
+	}
+	if subject != testClientSubject || serverName != "ignite.local" {
+		t.Errorf("handshake client certificate = %q, server name = %q, want %q, %q",
+			subject, serverName, testClientSubject, "ignite.local")
+	}
+
+	copyFile(t, testClientFile2, certFile)
+	if subject, _, err = tlsHandshake(t, client, server); err != nil {
+		t.Fatalf("handshake after rotation error = %v", err)
+	}
+	if subject != testClientSubject2 {
+		t.Errorf("handshake after rotation client certificate = %q, want %q", subject, testClientSubject2)
+	}
+
+	// server doesn't support client min version
+	server.MaxVersion = tls.VersionTLS11
+	if _, _, err = tlsHandshake(t, client, server); err == nil {
+		t.Errorf("handshake with unsupported TLS version error = nil")
+	}
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	b, err := ioutil.ReadFile(src)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if err = ioutil.WriteFile(dst, b, 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package v1
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"unicode"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+	"github.com/amsokol/ignite-go-client/binary/v1"
+)
+
+// DefaultBatchSize is max count of the parameter sets sent in one statement by ExecBatchContext
+const DefaultBatchSize = 1000
+
+// BatchExecer is implemented by connection which can execute query for many parameter sets
+type BatchExecer interface {
+	// ExecBatchContext executes query (usually INSERT or MERGE) for every parameter set of args
+	// and returns count of the affected rows for every statement sent to server.
+	ExecBatchContext(ctx context.Context, query string, args [][]interface{}) ([]int64, error)
+}
+
+// ExecBatchContext executes query for every parameter set of args.
+//
+// INSERT and MERGE queries with single VALUES tuple (e.g. "INSERT INTO T(ID, NAME) VALUES(?, ?)")
+// are rewritten into multi-row statements with up to DefaultBatchSize tuples,
+// other queries are executed once for every parameter set.
+// Count of the affected rows is returned for every statement sent to server.
+// If statement is failed, counts of the previous statements are returned with error.
+func (c *conn) ExecBatchContext(ctx context.Context, query string, args [][]interface{}) ([]int64, error) {
+	n := numInput(query)
+	if n < 0 {
+		return nil, errors.Errorf("parameters of different kinds can't be mixed in batch query")
+	}
+	rows := make([][]driver.Value, 0, len(args))
+	for i, a := range args {
+		if len(a) != n {
+			return nil, errors.Errorf("parameter set %d has %d arguments, but query has %d parameters", i, len(a), n)
+		}
+		row := make([]driver.Value, 0, n)
+		for j, v := range a {
+			v, err := ValueConverter.ConvertValue(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid argument %d of parameter set %d", j+1, i)
+			}
+			row = append(row, v)
+		}
+		rows = append(rows, row)
+	}
+
+	head, tuple, tail, ok := splitValues(query)
+	size := DefaultBatchSize
+	if !ok {
+		size = 1
+	}
+
+	counts := make([]int64, 0, (len(rows)+size-1)/size)
+	var batch string
+	for len(rows) > 0 {
+		count := size
+		if count > len(rows) {
+			count = len(rows)
+		}
+		q := query
+		if ok {
+			if len(batch) == 0 || count < size {
+				// text of the full batch is reused
+				batch = batchQuery(head, tuple, tail, count)
+			}
+			q = batch
+		}
+		values := make([]driver.Value, 0, count*n)
+		for _, row := range rows[:count] {
+			values = append(values, row...)
+		}
+		rows = rows[count:]
+
+		d, err := c.queryData(q, namedValues(values))
+		if err != nil {
+			return counts, err
+		}
+		d.PageSize = 10000
+		d.MaxRows = 0
+		d.StatementType = ignite.StatementTypeUpdate
+		res, err := c.exec(ctx, d)
+		if err != nil {
+			return counts, err
+		}
+		ra, _ := res.RowsAffected()
+		counts = append(counts, ra)
+	}
+	return counts, nil
+}
+
+// batchQuery returns query with count VALUES tuples
+func batchQuery(head, tuple, tail string, count int) string {
+	var b strings.Builder
+	b.Grow(len(head) + (len(tuple)+2)*count + len(tail))
+	b.WriteString(head)
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(tuple)
+	}
+	b.WriteString(tail)
+	return b.String()
+}
+
+// splitValues splits INSERT or MERGE query into the parts before, inside and after the last VALUES tuple.
+// ok is false if query can't be rewritten into multi-row statement:
+// it is not INSERT or MERGE, tuple is not at the end of query,
+// there are parameters outside the tuple, "?NNN" or named parameters.
+func splitValues(query string) (head, tuple, tail string, ok bool) {
+	keyword := stripComments(query)
+	if end := strings.IndexFunc(keyword, func(r rune) bool { return !unicode.IsLetter(r) }); end >= 0 {
+		keyword = keyword[:end]
+	}
+	if k := strings.ToUpper(keyword); k != "INSERT" && k != "MERGE" {
+		return "", "", "", false
+	}
+
+	// literals and comments are replaced by spaces to search keywords and parentheses
+	masked := []byte(query)
+	for i := 0; i < len(query); {
+		end, ok := skipLiteral(query, i)
+		if !ok {
+			i++
+			continue
+		}
+		for ; i < end; i++ {
+			masked[i] = ' '
+		}
+	}
+	upper := strings.ToUpper(string(masked))
+
+	// find the last VALUES keyword
+	values := -1
+	for i := strings.LastIndex(upper, "VALUES"); i >= 0; i = strings.LastIndex(upper[:i], "VALUES") {
+		if (i == 0 || !isIdentifier(upper[i-1])) && (i+6 == len(upper) || !isIdentifier(upper[i+6])) {
+			values = i
+			break
+		}
+	}
+	if values < 0 {
+		return "", "", "", false
+	}
+	start := values + 6
+	for start < len(upper) && unicode.IsSpace(rune(upper[start])) {
+		start++
+	}
+	if start == len(upper) || upper[start] != '(' {
+		return "", "", "", false
+	}
+	end, depth := start, 0
+	for ; end < len(upper); end++ {
+		if upper[end] == '(' {
+			depth++
+		} else if upper[end] == ')' {
+			if depth--; depth == 0 {
+				break
+			}
+		}
+	}
+	if end == len(upper) || len(strings.TrimRight(upper[end+1:], " \t\r\n;")) > 0 {
+		return "", "", "", false
+	}
+
+	// all parameters must be inside the tuple
+	ok = true
+	scanQuery(query, nil, func(p placeholder) {
+		if p.index != 0 || len(p.name) > 0 || p.start < start || p.end > end {
+			ok = false
+		}
+	})
+	if !ok {
+		return "", "", "", false
+	}
+	return query[:start], query[start : end+1], query[end+1:], true
+}
+
+// isIdentifier returns true if c is ASCII letter, digit or underscore
+func isIdentifier(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
@@ -0,0 +1,106 @@
+package v1
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+	"github.com/amsokol/ignite-go-client/ignitetest"
+	"github.com/amsokol/ignite-go-client/sql/common"
+)
+
+func Test_splitValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		head  string
+		tuple string
+		tail  string
+		ok    bool
+	}{
+		{
+			name:  "insert",
+			query: "INSERT INTO T(ID, NAME) VALUES(?, ?)",
+			head:  "INSERT INTO T(ID, NAME) VALUES", tuple: "(?, ?)", ok: true,
+		},
+		{
+			name:  "merge with functions and semicolon",
+			query: "merge into T(ID, CREATED) values (?, COALESCE(?, NOW())) ;",
+			head:  "merge into T(ID, CREATED) values ", tuple: "(?, COALESCE(?, NOW()))", tail: " ;", ok: true,
+		},
+		{
+			name:  "literals and comments",
+			query: "/* VALUES (?) */ INSERT INTO \"VALUES\"(ID, NAME) VALUES(?, 'x)') -- VALUES(?)",
+			head:  "/* VALUES (?) */ INSERT INTO \"VALUES\"(ID, NAME) VALUES", tuple: "(?, 'x)')", tail: " -- VALUES(?)", ok: true,
+		},
+		{name: "select", query: "SELECT * FROM T WHERE ID IN (VALUES(?))"},
+		{name: "insert from select", query: "INSERT INTO T(ID) SELECT ID FROM S WHERE ID > ?"},
+		{name: "parameter outside tuple", query: "INSERT INTO T(ID) VALUES(?) RETURNING ?"},
+		{name: "numbered parameters", query: "INSERT INTO T(ID, NAME) VALUES(?1, ?2)"},
+		{name: "multi-row insert", query: "INSERT INTO T(ID) VALUES(?), (?)"},
+		{name: "column named like keyword", query: "INSERT INTO T(VALUES_COUNT) SELECT ?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, tuple, tail, ok := splitValues(tt.query)
+			if head != tt.head || tuple != tt.tuple || tail != tt.tail || ok != tt.ok {
+				t.Errorf("splitValues() = %q, %q, %q, %v, want %q, %q, %q, %v",
+					head, tuple, tail, ok, tt.head, tt.tuple, tt.tail, tt.ok)
+			}
+		})
+	}
+}
+
+func Test_conn_ExecBatchContext(t *testing.T) {
+	type statement struct {
+		query string
+		args  int
+	}
+	var statements []statement
+	c := newTestConn(t, func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		statements = append(statements, statement{query: q.Query, args: len(q.Args)})
+		if len(q.Args) > 0 && q.Args[0] == "fail" {
+			return nil, errors.Errorf("failed")
+		}
+		return &ignitetest.SQLResult{Columns: []string{"UPDATED"},
+			Rows: [][]interface{}{{int64(strings.Count(q.Query, "(?, ?)"))}}}, nil
+	}, common.ConnInfo{})
+
+	args := make([][]interface{}, 0, DefaultBatchSize*2+1)
+	for i := 0; i < cap(args); i++ {
+		args = append(args, []interface{}{int64(i), "name"})
+	}
+	counts, err := c.ExecBatchContext(context.Background(), "INSERT INTO T(ID, NAME) VALUES(?, ?)", args)
+	if err != nil {
+		t.Fatalf("ExecBatchContext() error = %v", err)
+	}
+	if want := []int64{DefaultBatchSize, DefaultBatchSize, 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("ExecBatchContext() = %v, want %v", counts, want)
+	}
+	if len(statements) != 3 || statements[0].args != DefaultBatchSize*2 || statements[2].args != 2 ||
+		statements[2].query != "INSERT INTO T(ID, NAME) VALUES(?, ?)" {
+		t.Errorf("ExecBatchContext() sent %d statements", len(statements))
+	}
+
+	// query can't be rewritten, so it is executed for every parameter set
+	statements = nil
+	counts, err = c.ExecBatchContext(context.Background(), "UPDATE T SET NAME = ? WHERE ID = ?",
+		[][]interface{}{{"a", int64(1)}, {"b", int64(2)}, {"fail", int64(3)}})
+	if err == nil {
+		t.Fatalf("ExecBatchContext() error = nil")
+	}
+	if len(counts) != 2 || len(statements) != 3 {
+		t.Errorf("ExecBatchContext() = %v, sent %d statements, want 2 counts, 3 statements", counts, len(statements))
+	}
+
+	for _, args := range [][][]interface{}{
+		{{int64(1)}},
+		{{int64(1), struct{}{}}},
+	} {
+		if _, err = c.ExecBatchContext(context.Background(), "INSERT INTO T(ID, NAME) VALUES(?, ?)", args); err == nil {
+			t.Errorf("ExecBatchContext(%v) error = nil", args)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"reflect"
+
+	"github.com/amsokol/ignite-go-client/binary/v1"
+)
+
+// scanTypeAny is scan type of the column with unknown type
+var scanTypeAny = reflect.TypeOf(new(interface{})).Elem()
+
+// column is type of the result set column.
+// Protocol doesn't send column metadata, so type is detected by the first not null value of the column.
+type column struct {
+	// typeCode is type code of the first not null value, zero if it is unknown
+	typeCode byte
+	// scanType is Go type of the first not null value
+	scanType reflect.Type
+	// null is true if null value is received
+	null bool
+}
+
+// observe updates column type by value read from server
+func (c *column) observe(typeCode byte, v interface{}) {
+	if v == nil {
+		c.null = true
+		return
+	}
+	if c.typeCode == 0 {
+		c.typeCode = typeCode
+		c.scanType = reflect.TypeOf(v)
+	}
+}
+
+// merge updates column type by type detected by values of the other page
+func (c *column) merge(o column) {
+	if o.null {
+		c.null = true
+	}
+	if c.typeCode == 0 {
+		c.typeCode, c.scanType = o.typeCode, o.scanType
+	}
+}
+
+// ColumnTypeDatabaseTypeName returns the database system type name without the length,
+// e.g. "VARCHAR", "BIGINT", "TIMESTAMP". Type name of the collections and arrays is "ARRAY".
+// Empty string is returned if type is unknown (e.g. all received values are null).
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	return ignite.SQLTypeName(r.columns[index].typeCode)
+}
+
+// ColumnTypeScanType returns the value type that can be used to scan types into.
+// For example, the database column type "BIGINT" this should return "reflect.TypeOf(int64(0))".
+// Type of the empty interface is returned if type is unknown.
+func (r *rows) ColumnTypeScanType(index int) reflect.Type {
+	if t := r.columns[index].scanType; t != nil {
+		return t
+	}
+	return scanTypeAny
+}
+
+// ColumnTypeNullable returns true if it is known the column may be null,
+// or false if the column is known to be not nullable.
+// Column is known to be nullable if null value is received, otherwise nullability is unknown.
+func (r *rows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if r.columns[index].null {
+		return true, true
+	}
+	return false, false
+}
+
+// ColumnTypePrecisionScale returns the precision and scale for decimal types.
+// Protocol doesn't send precision and scale, so ok is always false.
+func (r *rows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	return 0, 0, false
+}
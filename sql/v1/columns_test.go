@@ -0,0 +1,85 @@
+package v1
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/amsokol/ignite-go-client/ignitetest"
+	"github.com/amsokol/ignite-go-client/sql/common"
+)
+
+func Test_rows_ColumnTypes(t *testing.T) {
+	ts := time.Date(2018, 4, 3, 14, 25, 32, 0, time.UTC)
+	c := newTestConn(t, func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		return &ignitetest.SQLResult{Columns: []string{"ID", "NAME", "CREATED", "RATE", "NOTE"},
+			Rows: [][]interface{}{
+				{int64(1), nil, ts, float64(0.5), nil},
+				{int64(2), "b", ts, float64(1.5), nil},
+				{int64(3), "c", ts, float64(2.5), nil},
+			}}, nil
+	}, common.ConnInfo{PageSize: 2})
+
+	dr, err := c.QueryContext(context.Background(), "SELECT ID, NAME, CREATED, RATE, NOTE FROM T", nil)
+	if err != nil {
+		t.Fatalf("QueryContext() error = %v", err)
+	}
+	defer dr.Close()
+	r := dr.(Rows)
+
+	type columnType struct {
+		name     string
+		scanType reflect.Type
+		nullable bool
+		ok       bool
+	}
+	check := func(want []columnType) {
+		t.Helper()
+		for i, w := range want {
+			got := columnType{name: r.ColumnTypeDatabaseTypeName(i), scanType: r.ColumnTypeScanType(i)}
+			got.nullable, got.ok = r.ColumnTypeNullable(i)
+			if !reflect.DeepEqual(got, w) {
+				t.Errorf("column %d type = %+v, want %+v", i, got, w)
+			}
+			if _, _, ok := r.ColumnTypePrecisionScale(i); ok {
+				t.Errorf("column %d ColumnTypePrecisionScale() ok = true", i)
+			}
+		}
+	}
+
+	// types are detected by the first row before Next is called
+	check([]columnType{
+		{name: "BIGINT", scanType: reflect.TypeOf(int64(0))},
+		{name: "", scanType: scanTypeAny, nullable: true, ok: true},
+		{name: "TIMESTAMP", scanType: reflect.TypeOf(time.Time{})},
+		{name: "DOUBLE", scanType: reflect.TypeOf(float64(0))},
+		{name: "", scanType: scanTypeAny, nullable: true, ok: true},
+	})
+
+	dest := make([]driver.Value, 5)
+	var ids []driver.Value
+	for {
+		if err = r.Next(dest); err != nil {
+			break
+		}
+		ids = append(ids, dest[0])
+	}
+	if err != io.EOF {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if !reflect.DeepEqual(ids, []driver.Value{int64(1), int64(2), int64(3)}) {
+		t.Errorf("Next() IDs = %v, want [1 2 3]", ids)
+	}
+
+	// type of the column is detected by the next rows if the first row has null value
+	check([]columnType{
+		{name: "BIGINT", scanType: reflect.TypeOf(int64(0))},
+		{name: "VARCHAR", scanType: reflect.TypeOf(""), nullable: true, ok: true},
+		{name: "TIMESTAMP", scanType: reflect.TypeOf(time.Time{})},
+		{name: "DOUBLE", scanType: reflect.TypeOf(float64(0))},
+		{name: "", scanType: scanTypeAny, nullable: true, ok: true},
+	})
+}
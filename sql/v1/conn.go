@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"runtime"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/amsokol/ignite-go-client/binary/errors"
@@ -20,11 +21,17 @@ type conn struct {
 	info    common.ConnInfo
 	client  ignite.Client
 
+	// cursors is IDs of the server cursors opened by the connection and not closed yet
+	cursors      map[int64]struct{}
+	cursorsMutex sync.Mutex
+
 	driver.Conn
 	driver.ExecerContext
 	driver.Pinger
 	driver.QueryerContext
 	driver.NamedValueChecker
+	driver.SessionResetter
+	driver.Validator
 }
 
 // isConnected return true if connection to the cluster is active
@@ -80,16 +87,148 @@ func (c *conn) Begin() (driver.Tx, error) {
 // <driver.ExecerContext>
 
 func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	d, err := c.queryData(query, args)
+	if err != nil {
+		return nil, err
+	}
+	d.PageSize = 10000
+	d.MaxRows = 0
+	d.StatementType = ignite.StatementTypeUpdate
+	return c.exec(ctx, d)
+}
+
+// </driver.ExecerContext>
+
+// <driver.Pinger>
+
+func (c *conn) Ping(ctx context.Context) error {
+	r, err := c.QueryContext(ctx, "SELECT 1", nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to execute ping query")
+	}
+	defer r.Close()
+	var dest [1]driver.Value
+	if err = r.Next(dest[:]); err != nil {
+		return errors.Wrapf(err, "failed to read ping query response")
+	}
+	if "1" != fmt.Sprintf("%v", dest[0]) {
+		return errors.Wrapf(err, "ping query returned unexpected value: %v", dest[0])
+	}
+	return nil
+}
+
+// </driver.Pinger>
+
+// <driver.QueryerContext>
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	d, err := c.queryData(query, args)
+	if err != nil {
+		return nil, err
+	}
+	d.StatementType = ignite.StatementTypeSelect
+	return c.query(ctx, d)
+}
+
+// </driver.QueryerContext>
+
+// <driver.NamedValueChecker>
+
+// CheckNamedValue is called before passing arguments to the driver
+// and is called in place of any ColumnConverter.
+//
+// Arguments are converted by ValueConverter, unsupported types are rejected before query is sent.
+func (c *conn) CheckNamedValue(val *driver.NamedValue) error {
+	v, err := ValueConverter.ConvertValue(val.Value)
+	if err != nil {
+		if len(val.Name) > 0 {
+			return errors.Wrapf(err, "invalid argument \"%s\"", val.Name)
+		}
+		return errors.Wrapf(err, "invalid argument %d", val.Ordinal)
+	}
+	val.Value = v
+	return nil
+}
+
+// </driver.NamedValueChecker>
+
+// <driver.SessionResetter>
+
+// ResetSession is called prior to executing a query on the connection
+// if the connection has been used before. If the driver returns ErrBadConn
+// the connection is discarded.
+//
+// Server cursors of the rows which are not closed (e.g. abandoned in the middle of result set) are closed.
+func (c *conn) ResetSession(ctx context.Context) error {
 	if !c.isConnected() {
-		return nil, driver.ErrBadConn
+		return driver.ErrBadConn
+	}
+	for _, id := range c.takeCursors() {
+		if err := c.resourceClose(id); err != nil && !errors.Is(err, errors.ErrResourceNotFound) {
+			// cursor may leak on server, so connection is discarded
+			return driver.ErrBadConn
+		}
 	}
+	return nil
+}
+
+// </driver.SessionResetter>
+
+// <driver.Validator>
 
+// IsValid is called prior to placing the connection into the
+// connection pool. The connection will be discarded if false is returned.
+//
+// Connection is invalid if it is closed or protocol stream is broken (e.g. socket is died or request is interrupted).
+func (c *conn) IsValid() bool {
+	return c.isConnected()
+}
+
+// </driver.Validator>
+
+// openCursor registers open server cursor
+func (c *conn) openCursor(id int64) {
+	c.cursorsMutex.Lock()
+	defer c.cursorsMutex.Unlock()
+
+	if c.cursors == nil {
+		c.cursors = map[int64]struct{}{}
+	}
+	c.cursors[id] = struct{}{}
+}
+
+// closeCursor unregisters server cursor closed by client or by server
+func (c *conn) closeCursor(id int64) {
+	c.cursorsMutex.Lock()
+	defer c.cursorsMutex.Unlock()
+
+	delete(c.cursors, id)
+}
+
+// takeCursors unregisters and returns IDs of the open server cursors
+func (c *conn) takeCursors() []int64 {
+	c.cursorsMutex.Lock()
+	defer c.cursorsMutex.Unlock()
+
+	ids := make([]int64, 0, len(c.cursors))
+	for id := range c.cursors {
+		ids = append(ids, id)
+	}
+	c.cursors = nil
+	return ids
+}
+
+// queryData returns query parameters with options of the connection
+func (c *conn) queryData(query string, args []driver.NamedValue) (ignite.QuerySQLFieldsData, error) {
+	query, args, err := bindNamed(query, args)
+	if err != nil {
+		return ignite.QuerySQLFieldsData{}, err
+	}
 	d := ignite.QuerySQLFieldsData{
 		Schema:           c.info.Schema,
-		PageSize:         10000,
-		MaxRows:          0,
+		PageSize:         c.info.PageSize,
+		MaxRows:          c.info.MaxRows,
 		Query:            query,
-		StatementType:    2, /* UPDATE */
 		DistributedJoins: c.info.DistributedJoins,
 		LocalQuery:       c.info.LocalQuery,
 		ReplicatedOnly:   c.info.ReplicatedOnly,
@@ -113,10 +252,31 @@ func (c *conn) ExecContext(ctx context.Context, query string, args []driver.Name
 			}
 		}
 	}
+	return d, nil
+}
 
-	res, err := c.client.QuerySQLFields(c.info.Cache, false, d)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to execute query")
+// exec executes query that doesn't return rows and returns count of affected rows
+func (c *conn) exec(ctx context.Context, d ignite.QuerySQLFieldsData) (driver.Result, error) {
+	if !c.isConnected() {
+		return nil, driver.ErrBadConn
+	}
+
+	if err := applyQueryOptions(ctx, &d, false); err != nil {
+		return nil, err
+	}
+	var err error
+	if d.Timeout, err = queryTimeout(ctx, d.Timeout); err != nil {
+		return nil, err
+	}
+	var res ignite.QuerySQLFieldsResult
+	if err = c.run(ctx, func() error {
+		res, err = c.client.QuerySQLFields(c.info.Cache, false, d)
+		return err
+	}); err != nil {
+		if err == ctx.Err() {
+			return nil, err
+		}
+		return nil, common.NewError(errors.Wrapf(err, "failed to execute query"))
 	}
 
 	if res.HasMore {
@@ -147,89 +307,107 @@ func (c *conn) ExecContext(ctx context.Context, query string, args []driver.Name
 	}
 }
 
-// </driver.ExecerContext>
-
-// <driver.Pinger>
+// query executes query that returns rows
+func (c *conn) query(ctx context.Context, d ignite.QuerySQLFieldsData) (driver.Rows, error) {
+	if !c.isConnected() {
+		return nil, driver.ErrBadConn
+	}
 
-func (c *conn) Ping(ctx context.Context) error {
-	r, err := c.QueryContext(ctx, "SELECT 1", nil)
-	if err != nil {
-		return errors.Wrapf(err, "failed to execute ping query")
+	if err := applyQueryOptions(ctx, &d, true); err != nil {
+		return nil, err
 	}
-	var dest [1]driver.Value
-	if err = r.Next(dest[:]); err != nil {
-		return errors.Wrapf(err, "failed to read ping query response")
+	var err error
+	if d.Timeout, err = queryTimeout(ctx, d.Timeout); err != nil {
+		return nil, err
 	}
-	if "1" != fmt.Sprintf("%v", dest[0]) {
-		return errors.Wrapf(err, "ping query returned unexpected value: %v", dest[0])
+	d.IncludeFieldNames = true
+	var r *ignite.ResponseOperation
+	if err = c.run(ctx, func() error {
+		r, err = c.client.QuerySQLFieldsRaw(c.info.Cache, false, d)
+		return err
+	}); err != nil {
+		if err == ctx.Err() {
+			return nil, err
+		}
+		return nil, common.NewError(errors.Wrapf(err, "failed to execute query"))
 	}
-	return nil
-}
-
-// </driver.Pinger>
 
-// <driver.QueryerContext>
+	return newRows(ctx, c, r, prefetchPages(ctx, c.info.PrefetchPages))
+}
 
-func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
-	if !c.isConnected() {
-		return nil, driver.ErrBadConn
+// queryTimeout returns query timeout in milliseconds limited by context deadline
+func queryTimeout(ctx context.Context, timeout int64) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
 	}
-
-	d := ignite.QuerySQLFieldsData{
-		Schema:            c.info.Schema,
-		PageSize:          c.info.PageSize,
-		MaxRows:           c.info.MaxRows,
-		Query:             query,
-		StatementType:     1, /* SELECT */
-		DistributedJoins:  c.info.DistributedJoins,
-		LocalQuery:        c.info.LocalQuery,
-		ReplicatedOnly:    c.info.ReplicatedOnly,
-		EnforceJoinOrder:  c.info.EnforceJoinOrder,
-		Collocated:        c.info.Collocated,
-		LazyQuery:         c.info.LazyQuery,
-		Timeout:           c.info.Timeout,
-		IncludeFieldNames: true,
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return timeout, nil
 	}
-	if args != nil {
-		l := len(args)
-		d.QueryArgs = make([]interface{}, 0, l)
-		if l > 0 {
-			// sort slice
-			tmp := make([]driver.NamedValue, l)
-			copy(tmp, args)
-			sort.Slice(tmp, func(i, j int) bool {
-				return tmp[i].Ordinal < tmp[j].Ordinal
-			})
-			for i := 0; i < l; i++ {
-				d.QueryArgs = append(d.QueryArgs, tmp[i].Value)
-			}
-		}
+	ms := int64(time.Until(deadline) / time.Millisecond)
+	if ms <= 0 {
+		<-ctx.Done()
+		return 0, ctx.Err()
 	}
-
-	r, err := c.client.QuerySQLFieldsRaw(c.info.Cache, false, d)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to execute query")
+	if timeout <= 0 || ms < timeout {
+		return ms, nil
 	}
-
-	return newRows(c, r)
+	return timeout, nil
 }
 
-// </driver.QueryerContext>
-
-// <driver.NamedValueChecker>
+// run calls f which sends requests to server. Sending and receiving are interrupted when ctx is done.
+// Interrupted request may leave protocol stream mid-message, so connection is closed
+// (server releases its cursors) and ctx.Err() is returned.
+func (c *conn) run(ctx context.Context, f func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ctx.Done() == nil {
+		return f()
+	}
 
-func (c *conn) CheckNamedValue(val *driver.NamedValue) error {
-	// Ignore and handle later
-	return nil
+	client := c.client
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline {
+		_ = client.SetDeadline(deadline)
+	}
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			// interrupt blocked read or write
+			_ = client.SetDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+	err := f()
+	close(stop)
+	<-stopped
+
+	if err != nil && hasDeadline && !time.Now().Before(deadline) {
+		// request is failed by deadline, wait for context to be done
+		<-ctx.Done()
+	}
+	if ctx.Err() != nil && (err != nil || !client.Connected()) {
+		c.Close()
+		return ctx.Err()
+	}
+	_ = client.SetDeadline(time.Time{})
+	return err
 }
 
-// </driver.NamedValueChecker>
-
 func (c *conn) QueryNexPageContext(ctx context.Context, cursorID int64) (*ignite.ResponseOperation, error) {
 	if !c.isConnected() {
 		return nil, driver.ErrBadConn
 	}
-	return c.client.QuerySQLFieldsCursorGetPageRaw(cursorID)
+	var r *ignite.ResponseOperation
+	err := c.run(ctx, func() error {
+		var err error
+		r, err = c.client.QuerySQLFieldsCursorGetPageRaw(cursorID)
+		return err
+	})
+	return r, err
 }
 
 // Connect opens connection with protocol version v1
@@ -240,7 +418,7 @@ func Connect(ci common.ConnInfo) (driver.Conn, error) {
 
 	client, err := ignite.Connect(ci.ConnInfo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %v", err)
+		return nil, errors.Wrapf(err, "failed to create client")
 	}
 
 	c := &conn{info: ci, client: client, debugID: ci.URL}
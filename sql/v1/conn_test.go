@@ -8,7 +8,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/amsokol/ignite-go-client/binary/errors"
 	"github.com/amsokol/ignite-go-client/binary/v1"
+	"github.com/amsokol/ignite-go-client/ignitetest"
 	"github.com/amsokol/ignite-go-client/sql/common"
 )
 
@@ -378,3 +380,273 @@ func Test_conn_Ping(t *testing.T) {
 		})
 	}
 }
+
+func Test_queryTimeout(t *testing.T) {
+	background := context.Background()
+	short, cancel := context.WithTimeout(background, 2*time.Second)
+	defer cancel()
+	canceled, cancel := context.WithCancel(background)
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		timeout int64
+		min     int64
+		max     int64
+		wantErr error
+	}{
+		{name: "no deadline", ctx: background, timeout: 500, min: 500, max: 500},
+		{name: "no deadline, no timeout", ctx: background, min: 0, max: 0},
+		{name: "deadline", ctx: short, min: 1, max: 2000},
+		{name: "timeout less than deadline", ctx: short, timeout: 500, min: 500, max: 500},
+		{name: "deadline less than timeout", ctx: short, timeout: 60000, min: 1, max: 2000},
+		{name: "canceled", ctx: canceled, timeout: 500, wantErr: context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := queryTimeout(tt.ctx, tt.timeout)
+			if err != tt.wantErr {
+				t.Fatalf("queryTimeout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got < tt.min || got > tt.max) {
+				t.Errorf("queryTimeout() = %d, want in [%d, %d]", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func Test_conn_QueryContext_deadline(t *testing.T) {
+	timeouts := make(chan time.Duration, 1)
+	release := make(chan struct{})
+	c := newTestConn(t, func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		timeouts <- q.Timeout
+		<-release
+		return &ignitetest.SQLResult{Columns: []string{"ID"}}, nil
+	}, common.ConnInfo{})
+	t.Cleanup(func() { close(release) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.QueryContext(ctx, "SELECT ID FROM T", nil)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("QueryContext() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("QueryContext() returned after %s", d)
+	}
+	if timeout := <-timeouts; timeout <= 0 || timeout > 100*time.Millisecond {
+		t.Errorf("query timeout = %s, want in (0, 100ms]", timeout)
+	}
+	// interrupted connection must be discarded by database/sql
+	if _, err = c.QueryContext(context.Background(), "SELECT ID FROM T", nil); err != driver.ErrBadConn {
+		t.Errorf("QueryContext() after deadline error = %v, want %v", err, driver.ErrBadConn)
+	}
+}
+
+func Test_conn_ExecContext_cancel(t *testing.T) {
+	called := make(chan struct{})
+	release := make(chan struct{})
+	c := newTestConn(t, func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		close(called)
+		<-release
+		return &ignitetest.SQLResult{Columns: []string{"UPDATED"}, Rows: [][]interface{}{{int64(1)}}}, nil
+	}, common.ConnInfo{})
+	t.Cleanup(func() { close(release) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-called
+		cancel()
+	}()
+	if _, err := c.ExecContext(ctx, "DELETE FROM T", nil); err != context.Canceled {
+		t.Fatalf("ExecContext() error = %v, want %v", err, context.Canceled)
+	}
+	if c.isConnected() {
+		t.Errorf("connection is not closed after cancellation")
+	}
+	if _, err := c.ExecContext(ctx, "DELETE FROM T", nil); err != driver.ErrBadConn {
+		t.Errorf("ExecContext() after cancellation error = %v, want %v", err, driver.ErrBadConn)
+	}
+}
+
+func Test_rows_Next_cancel(t *testing.T) {
+	srv, err := ignitetest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	defer srv.Close()
+	srv.SQL = func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		return &ignitetest.SQLResult{Columns: []string{"ID"},
+			Rows: [][]interface{}{{int64(1)}, {int64(2)}, {int64(3)}, {int64(4)}, {int64(5)}}}, nil
+	}
+	ci := common.ConnInfo{ConnInfo: srv.ConnInfo(), PageSize: 2}
+	dc, err := Connect(ci)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer dc.Close()
+	c := dc.(*conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r, err := c.QueryContext(ctx, "SELECT ID FROM T", nil)
+	if err != nil {
+		t.Fatalf("QueryContext() error = %v", err)
+	}
+	if n := srv.OpenCursors(); n != 1 {
+		t.Fatalf("OpenCursors() = %d, want 1", n)
+	}
+	dest := make([]driver.Value, 1)
+	if err = r.Next(dest); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	cancel()
+	// rows of the fetched page are available
+	if err = r.Next(dest); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if err = r.Next(dest); err != context.Canceled {
+		t.Fatalf("Next() error = %v, want %v", err, context.Canceled)
+	}
+	if n := srv.OpenCursors(); n != 0 {
+		t.Errorf("OpenCursors() = %d, want 0", n)
+	}
+	if err = r.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if !c.isConnected() {
+		t.Errorf("connection is closed")
+	}
+}
+
+func Test_rows_Close(t *testing.T) {
+	c := newTestConn(t, func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		return &ignitetest.SQLResult{Columns: []string{"ID"},
+			Rows: [][]interface{}{{int64(1)}, {int64(2)}, {int64(3)}}}, nil
+	}, common.ConnInfo{PageSize: 2})
+
+	for _, read := range []int{0, 2, 4} {
+		r, err := c.QueryContext(context.Background(), "SELECT ID FROM T", nil)
+		if err != nil {
+			t.Fatalf("QueryContext() error = %v", err)
+		}
+		dest := make([]driver.Value, 1)
+		for i := 0; i < read; i++ {
+			_ = r.Next(dest)
+		}
+		if err = r.Close(); err != nil {
+			t.Errorf("Close() after %d rows error = %v", read, err)
+		}
+	}
+}
+
+func Test_conn_ResetSession(t *testing.T) {
+	srv, err := ignitetest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	defer srv.Close()
+	srv.SQL = func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		return &ignitetest.SQLResult{Columns: []string{"ID"},
+			Rows: [][]interface{}{{int64(1)}, {int64(2)}, {int64(3)}}}, nil
+	}
+	ci := common.ConnInfo{ConnInfo: srv.ConnInfo(), PageSize: 2}
+	dc, err := Connect(ci)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer dc.Close()
+	c := dc.(*conn)
+
+	// abandoned in the middle of result set
+	r1, err := c.QueryContext(context.Background(), "SELECT ID FROM T", nil)
+	if err != nil {
+		t.Fatalf("QueryContext() error = %v", err)
+	}
+	// read to the end
+	r2, err := c.QueryContext(context.Background(), "SELECT ID FROM T", nil)
+	if err != nil {
+		t.Fatalf("QueryContext() error = %v", err)
+	}
+	dest := make([]driver.Value, 1)
+	for r2.Next(dest) == nil {
+	}
+	// closed by client
+	r3, err := c.QueryContext(context.Background(), "SELECT ID FROM T", nil)
+	if err != nil {
+		t.Fatalf("QueryContext() error = %v", err)
+	}
+	if err = r3.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if n := srv.OpenCursors(); n != 1 {
+		t.Fatalf("OpenCursors() = %d, want 1", n)
+	}
+
+	if err = c.ResetSession(context.Background()); err != nil {
+		t.Fatalf("ResetSession() error = %v", err)
+	}
+	if n := srv.OpenCursors(); n != 0 {
+		t.Errorf("OpenCursors() = %d, want 0", n)
+	}
+	if !c.IsValid() {
+		t.Errorf("IsValid() = false, want true")
+	}
+	// cursor is already closed by ResetSession
+	if err = r1.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+
+	if err = c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if c.IsValid() {
+		t.Errorf("IsValid() after Close() = true, want false")
+	}
+	if err = c.ResetSession(context.Background()); err != driver.ErrBadConn {
+		t.Errorf("ResetSession() after Close() error = %v, want %v", err, driver.ErrBadConn)
+	}
+}
+
+func Test_conn_IsValid_broken(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	c := newTestConn(t, func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		<-block
+		return &ignitetest.SQLResult{Columns: []string{"ID"}}, nil
+	}, common.ConnInfo{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.QueryContext(ctx, "SELECT ID FROM T", nil); err != context.DeadlineExceeded {
+		t.Fatalf("QueryContext() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	// protocol stream is interrupted in the middle of request
+	if c.IsValid() {
+		t.Errorf("IsValid() = true, want false")
+	}
+	if err := c.ResetSession(context.Background()); err != driver.ErrBadConn {
+		t.Errorf("ResetSession() error = %v, want %v", err, driver.ErrBadConn)
+	}
+}
+
+func Test_conn_error(t *testing.T) {
+	c := newTestConn(t, func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		return nil, errors.Errorf("Duplicate key during INSERT [key=1]")
+	}, common.ConnInfo{})
+
+	_, err := c.ExecContext(context.Background(), "INSERT INTO T(ID) VALUES(1)", nil)
+	var e *common.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("ExecContext() error = %v, want *common.Error", err)
+	}
+	if e.SQLState != common.SQLStateDuplicateKey || e.Code != errors.StatusFailed ||
+		e.Message != "Duplicate key during INSERT [key=1]" {
+		t.Errorf("ExecContext() error = %+v", e)
+	}
+	if _, err = c.QueryContext(context.Background(), "SELECT ID FROM T", nil); !errors.As(err, &e) {
+		t.Errorf("QueryContext() error = %v, want *common.Error", err)
+	}
+}
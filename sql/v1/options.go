@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"context"
+	"time"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+	"github.com/amsokol/ignite-go-client/binary/v1"
+	"github.com/amsokol/ignite-go-client/sql/common"
+)
+
+// applyQueryOptions overrides query parameters by options of the context (see common.WithQueryOptions).
+// Page size and max rows are overridden for queries that return rows only.
+func applyQueryOptions(ctx context.Context, d *ignite.QuerySQLFieldsData, rows bool) error {
+	o, ok := common.QueryOptionsFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if rows && o.PageSize != nil {
+		if *o.PageSize <= 0 {
+			return errors.Errorf("invalid page size option: %d", *o.PageSize)
+		}
+		d.PageSize = *o.PageSize
+	}
+	if rows && o.MaxRows != nil {
+		if *o.MaxRows < 0 {
+			return errors.Errorf("invalid max rows option: %d", *o.MaxRows)
+		}
+		d.MaxRows = *o.MaxRows
+	}
+	if rows && o.PrefetchPages != nil && *o.PrefetchPages < 0 {
+		return errors.Errorf("invalid prefetch pages option: %d", *o.PrefetchPages)
+	}
+	if o.Timeout != nil {
+		if *o.Timeout < 0 {
+			return errors.Errorf("invalid timeout option: %v", *o.Timeout)
+		}
+		// timeout less than millisecond must not disable timeout
+		d.Timeout = int64((*o.Timeout + time.Millisecond - 1) / time.Millisecond)
+	}
+	if o.DistributedJoins != nil {
+		d.DistributedJoins = *o.DistributedJoins
+	}
+	if o.LocalQuery != nil {
+		d.LocalQuery = *o.LocalQuery
+	}
+	if o.ReplicatedOnly != nil {
+		d.ReplicatedOnly = *o.ReplicatedOnly
+	}
+	if o.EnforceJoinOrder != nil {
+		d.EnforceJoinOrder = *o.EnforceJoinOrder
+	}
+	if o.Collocated != nil {
+		d.Collocated = *o.Collocated
+	}
+	if o.LazyQuery != nil {
+		d.LazyQuery = *o.LazyQuery
+	}
+	return nil
+}
+
+// prefetchPages returns count of the pages to fetch in background, pages is overridden by options of the context
+func prefetchPages(ctx context.Context, pages int) int {
+	if o, ok := common.QueryOptionsFromContext(ctx); ok && o.PrefetchPages != nil {
+		return *o.PrefetchPages
+	}
+	return pages
+}
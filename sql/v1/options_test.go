@@ -0,0 +1,147 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amsokol/ignite-go-client/binary/v1"
+	"github.com/amsokol/ignite-go-client/ignitetest"
+	"github.com/amsokol/ignite-go-client/sql/common"
+)
+
+func Test_conn_queryOptions(t *testing.T) {
+	var queries []ignitetest.SQLQuery
+	c := newTestConn(t, func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		queries = append(queries, q)
+		return &ignitetest.SQLResult{Columns: []string{"ID"}}, nil
+	}, common.ConnInfo{PageSize: 10, MaxRows: 100, Timeout: 1000, Collocated: true})
+
+	pageSize, maxRows, timeout := 500, 0, 30*time.Second
+	yes, no := true, false
+	ctx := common.WithQueryOptions(context.Background(), common.QueryOptions{
+		PageSize: &pageSize, DistributedJoins: &yes, Collocated: &no})
+	// nested options override the parent ones
+	ctx = common.WithQueryOptions(ctx, common.QueryOptions{
+		MaxRows: &maxRows, Timeout: &timeout, LazyQuery: &yes})
+
+	tests := []struct {
+		name string
+		run  func(ctx context.Context) error
+		want ignitetest.SQLQuery
+	}{
+		{
+			name: "query without options",
+			run: func(ctx context.Context) error {
+				_, err := c.QueryContext(context.Background(), "SELECT ID FROM T", nil)
+				return err
+			},
+			want: ignitetest.SQLQuery{PageSize: 10, MaxRows: 100, Timeout: time.Second, Collocated: true},
+		},
+		{
+			name: "query",
+			run: func(ctx context.Context) error {
+				_, err := c.QueryContext(ctx, "SELECT ID FROM T", nil)
+				return err
+			},
+			want: ignitetest.SQLQuery{PageSize: 500, MaxRows: 0, Timeout: 30 * time.Second,
+				DistributedJoins: true, LazyQuery: true},
+		},
+		{
+			name: "exec",
+			run: func(ctx context.Context) error {
+				_, err := c.ExecContext(ctx, "DELETE FROM T", nil)
+				return err
+			},
+			want: ignitetest.SQLQuery{PageSize: 10000, MaxRows: 0, Timeout: 30 * time.Second,
+				DistributedJoins: true, LazyQuery: true},
+		},
+		{
+			name: "statement",
+			run: func(ctx context.Context) error {
+				s, err := c.Prepare("SELECT ID FROM T")
+				if err != nil {
+					return err
+				}
+				defer s.Close()
+				_, err = s.(*stmt).QueryContext(ctx, nil)
+				return err
+			},
+			want: ignitetest.SQLQuery{PageSize: 500, MaxRows: 0, Timeout: 30 * time.Second,
+				DistributedJoins: true, LazyQuery: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries = nil
+			if err := tt.run(ctx); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if len(queries) != 1 {
+				t.Fatalf("%d queries are sent, want 1", len(queries))
+			}
+			got := queries[0]
+			if got.PageSize != tt.want.PageSize || got.MaxRows != tt.want.MaxRows || got.Timeout != tt.want.Timeout {
+				t.Errorf("page size, max rows, timeout = %d, %d, %v, want %d, %d, %v",
+					got.PageSize, got.MaxRows, got.Timeout, tt.want.PageSize, tt.want.MaxRows, tt.want.Timeout)
+			}
+			if got.DistributedJoins != tt.want.DistributedJoins || got.Collocated != tt.want.Collocated ||
+				got.LazyQuery != tt.want.LazyQuery || got.LocalQuery || got.ReplicatedOnly || got.EnforceJoinOrder {
+				t.Errorf("flags = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_applyQueryOptions(t *testing.T) {
+	negative := -1
+	negativeTimeout, shortTimeout := -time.Second, time.Microsecond
+	tests := []struct {
+		name    string
+		opts    common.QueryOptions
+		rows    bool
+		want    ignite.QuerySQLFieldsData
+		wantErr bool
+	}{
+		{
+			name: "page size and max rows of exec",
+			opts: common.QueryOptions{PageSize: &negative, MaxRows: &negative},
+			want: ignite.QuerySQLFieldsData{PageSize: 10, MaxRows: 20, Timeout: 30},
+		},
+		{
+			name: "timeout less than millisecond",
+			opts: common.QueryOptions{Timeout: &shortTimeout},
+			rows: true,
+			want: ignite.QuerySQLFieldsData{PageSize: 10, MaxRows: 20, Timeout: 1},
+		},
+		{
+			name:    "negative page size",
+			opts:    common.QueryOptions{PageSize: &negative},
+			rows:    true,
+			wantErr: true,
+		},
+		{
+			name:    "negative max rows",
+			opts:    common.QueryOptions{MaxRows: &negative},
+			rows:    true,
+			wantErr: true,
+		},
+		{
+			name:    "negative timeout",
+			opts:    common.QueryOptions{Timeout: &negativeTimeout},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ignite.QuerySQLFieldsData{PageSize: 10, MaxRows: 20, Timeout: 30}
+			err := applyQueryOptions(common.WithQueryOptions(context.Background(), tt.opts), &d, tt.rows)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyQueryOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (d.PageSize != tt.want.PageSize || d.MaxRows != tt.want.MaxRows || d.Timeout != tt.want.Timeout) {
+				t.Errorf("applyQueryOptions() = %+v, want %+v", d, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package v1
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+	"github.com/amsokol/ignite-go-client/binary/v1"
+	"github.com/amsokol/ignite-go-client/sql/common"
+)
+
+// page is decoded page of the cursor
+type page struct {
+	rows [][]driver.Value
+	// columns is column types detected by values of the page
+	columns []column
+	// hasMore is true if cursor has more pages
+	hasMore bool
+	// err is error of fetching or decoding the page
+	err error
+}
+
+// readPage reads rows and more records flag of the page
+func readPage(r *ignite.ResponseOperation, fieldCount int) (page, error) {
+	p := page{columns: make([]column, fieldCount)}
+	rowCount, err := ignite.ReadInt(r)
+	if err != nil {
+		return p, errors.Wrapf(err, "failed to read row count")
+	}
+	p.rows = make([][]driver.Value, 0, int(rowCount))
+	for i := 0; i < int(rowCount); i++ {
+		row := make([]driver.Value, fieldCount)
+		if err = readValues(r, row, p.columns); err != nil {
+			return p, err
+		}
+		p.rows = append(p.rows, row)
+	}
+	if p.hasMore, err = ignite.ReadBool(r); err != nil {
+		return p, errors.Wrapf(err, "failed to read more records flag")
+	}
+	return p, nil
+}
+
+// prefetcher fetches pages of the cursor in background
+type prefetcher struct {
+	// pages is buffer of the fetched pages, it is closed when the last page is sent or prefetcher is stopped
+	pages chan page
+	// stop stops fetching after the current request
+	stop chan struct{}
+	// stopped is closed when fetching goroutine is finished
+	stopped chan struct{}
+}
+
+// startPrefetch starts fetching of the next pages of the cursor, up to count pages are buffered.
+// Requests are interrupted by ctx like requests of Next without prefetching.
+func startPrefetch(ctx context.Context, conn *conn, id int64, fieldCount int, count int) *prefetcher {
+	p := &prefetcher{pages: make(chan page, count), stop: make(chan struct{}), stopped: make(chan struct{})}
+	go func() {
+		defer close(p.stopped)
+		defer close(p.pages)
+		for {
+			select {
+			case <-p.stop:
+				return
+			default:
+			}
+			var pg page
+			if err := ctx.Err(); err != nil {
+				pg.err = err
+			} else if r, err := conn.QueryNexPageContext(ctx, id); err != nil {
+				if err == ctx.Err() {
+					pg.err = err
+				} else {
+					pg.err = common.NewError(errors.Wrapf(err, "failed to read cursor page"))
+				}
+			} else {
+				pg, pg.err = readPage(r, fieldCount)
+			}
+			select {
+			case p.pages <- pg:
+			case <-p.stop:
+				return
+			}
+			if pg.err != nil || !pg.hasMore {
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// close stops fetching and waits for the current request, so connection can be used by caller
+func (p *prefetcher) close() {
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+	<-p.stopped
+}
+
+// nextPrefetched populates the next row from the decoded pages
+func (r *rows) nextPrefetched(dest []driver.Value) error {
+	for r.pos == len(r.page.rows) {
+		if r.done {
+			return io.EOF
+		}
+		if !r.page.hasMore {
+			// server closes cursor after the last page
+			r.finish()
+			return io.EOF
+		}
+		if err := r.ctx.Err(); err != nil {
+			// prevent resource leak on server
+			_ = r.Close()
+			return err
+		}
+		pg, ok := <-r.prefetch.pages
+		if !ok {
+			return io.EOF
+		}
+		if pg.err != nil {
+			// prevent resource leak on server
+			_ = r.Close()
+			return pg.err
+		}
+		for i := range r.columns {
+			r.columns[i].merge(pg.columns[i])
+		}
+		r.page, r.pos = pg, 0
+	}
+	if len(r.fields) != len(dest) {
+		return errors.Errorf("destination slice size must be %d but got %d", len(r.fields), len(dest))
+	}
+	copy(dest, r.page.rows[r.pos])
+	r.page.rows[r.pos] = nil
+	r.pos++
+	return nil
+}
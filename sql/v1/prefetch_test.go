@@ -0,0 +1,123 @@
+package v1
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/amsokol/ignite-go-client/ignitetest"
+	"github.com/amsokol/ignite-go-client/sql/common"
+)
+
+func Test_rows_prefetch(t *testing.T) {
+	result := &ignitetest.SQLResult{Columns: []string{"ID", "NAME"},
+		Rows: [][]interface{}{{int64(1), nil}, {int64(2), nil}, {int64(3), "C"},
+			{int64(4), "D"}, {int64(5), nil}, {int64(6), "F"}, {int64(7), "G"}}}
+	want := make([][]driver.Value, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		want = append(want, []driver.Value{row[0], row[1]})
+	}
+
+	for _, pages := range []int{0, 1, 2, 10} {
+		c := newTestConn(t, func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+			return result, nil
+		}, common.ConnInfo{PageSize: 2, PrefetchPages: pages})
+
+		for i := 0; i < 2; i++ {
+			r, err := c.QueryContext(context.Background(), "SELECT ID, NAME FROM T", nil)
+			if err != nil {
+				t.Fatalf("QueryContext() with %d pages error = %v", pages, err)
+			}
+			rs := r.(*rows)
+			if rs.ColumnTypeDatabaseTypeName(0) != "BIGINT" {
+				t.Errorf("ColumnTypeDatabaseTypeName(0) with %d pages = %s, want BIGINT", pages, rs.ColumnTypeDatabaseTypeName(0))
+			}
+			var got [][]driver.Value
+			for {
+				dest := make([]driver.Value, 2)
+				if err = r.Next(dest); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("Next() with %d pages error = %v", pages, err)
+				}
+				got = append(got, dest)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("rows with %d pages = %v, want %v", pages, got, want)
+			}
+			if rs.ColumnTypeDatabaseTypeName(1) != "VARCHAR" {
+				t.Errorf("ColumnTypeDatabaseTypeName(1) with %d pages = %s, want VARCHAR", pages, rs.ColumnTypeDatabaseTypeName(1))
+			}
+			if nullable, ok := rs.ColumnTypeNullable(1); !nullable || !ok {
+				t.Errorf("ColumnTypeNullable(1) with %d pages = %v, %v, want true, true", pages, nullable, ok)
+			}
+			if err = r.Close(); err != nil {
+				t.Errorf("Close() with %d pages error = %v", pages, err)
+			}
+		}
+	}
+}
+
+func Test_rows_prefetch_Close(t *testing.T) {
+	srv, err := ignitetest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	defer srv.Close()
+	srv.SQL = func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		return &ignitetest.SQLResult{Columns: []string{"ID"},
+			Rows: [][]interface{}{{int64(1)}, {int64(2)}, {int64(3)}, {int64(4)}, {int64(5)}, {int64(6)}}}, nil
+	}
+	dc, err := Connect(common.ConnInfo{ConnInfo: srv.ConnInfo(), PageSize: 1, PrefetchPages: 2})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer dc.Close()
+	c := dc.(*conn)
+
+	dest := make([]driver.Value, 1)
+	for _, read := range []int{0, 1, 3} {
+		r, err := c.QueryContext(context.Background(), "SELECT ID FROM T", nil)
+		if err != nil {
+			t.Fatalf("QueryContext() error = %v", err)
+		}
+		for i := 0; i < read; i++ {
+			if err = r.Next(dest); err != nil {
+				t.Fatalf("Next() error = %v", err)
+			}
+		}
+		if err = r.Close(); err != nil {
+			t.Errorf("Close() after %d rows error = %v", read, err)
+		}
+		if n := srv.OpenCursors(); n != 0 {
+			t.Errorf("OpenCursors() after %d rows = %d, want 0", read, n)
+		}
+		if !c.IsValid() {
+			t.Fatalf("IsValid() after %d rows = false, want true", read)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r, err := c.QueryContext(ctx, "SELECT ID FROM T", nil)
+	if err != nil {
+		t.Fatalf("QueryContext() error = %v", err)
+	}
+	if err = r.Next(dest); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	cancel()
+	for err == nil {
+		err = r.Next(dest)
+	}
+	if err != context.Canceled {
+		t.Errorf("Next() error = %v, want %v", err, context.Canceled)
+	}
+	if err = r.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if n := srv.OpenCursors(); n != 0 {
+		t.Errorf("OpenCursors() = %d, want 0", n)
+	}
+}
@@ -0,0 +1,273 @@
+package v1
+
+import (
+	"database/sql/driver"
+	"strings"
+	"unicode"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+	"github.com/amsokol/ignite-go-client/binary/v1"
+)
+
+// placeholder is position of the query parameter in the query text
+type placeholder struct {
+	// start and end are byte offsets of the placeholder
+	start, end int
+	// index is 1-based index of "?NNN" parameter, zero for "?"
+	index int
+	// name is name of ":name" or "@name" parameter
+	name string
+	// variable is true for "@name" parameter, it is H2 session variable if there is no argument with the name
+	variable bool
+}
+
+// scanQuery splits the query by "?", "?NNN", ":name" and "@name" placeholders.
+// Placeholders inside string literals, quoted identifiers and comments are ignored.
+// "@name" may be H2 session variable (e.g. "SET @x = 1; SELECT @x"), it is resolved by bindNamed.
+// text is called for parts of the query between placeholders (if not nil), param is called for placeholders (if not nil).
+func scanQuery(query string, text func(s string), param func(p placeholder)) {
+	last := 0
+	for i := 0; i < len(query); {
+		if end, ok := skipLiteral(query, i); ok {
+			i = end
+			continue
+		}
+		var p placeholder
+		switch c := query[i]; {
+		case (c == ':' || c == '@') && i+1 < len(query) && query[i+1] == c:
+			// "::" cast or "@@" system variable
+			i += 2
+			continue
+		case (c == ':' || c == '@') && i+1 < len(query) && isNameStart(query[i+1]):
+			p = placeholder{start: i, end: i + 2, variable: c == '@'}
+			for p.end < len(query) && isIdentifier(query[p.end]) {
+				p.end++
+			}
+			p.name = query[i+1 : p.end]
+		case c == '?':
+			p = placeholder{start: i, end: i + 1}
+			for p.end < len(query) && query[p.end] >= '0' && query[p.end] <= '9' {
+				p.index = p.index*10 + int(query[p.end]-'0')
+				p.end++
+			}
+		default:
+			i++
+			continue
+		}
+		if text != nil {
+			text(query[last:p.start])
+		}
+		if param != nil {
+			param(p)
+		}
+		last, i = p.end, p.end
+	}
+	if text != nil {
+		text(query[last:])
+	}
+}
+
+// skipLiteral returns index after string literal, quoted identifier or comment started at i.
+// ok is false if there is no literal or comment at i.
+func skipLiteral(query string, i int) (end int, ok bool) {
+	switch c := query[i]; {
+	case c == '\'' || c == '"' || c == '`':
+		return skipQuoted(query, i, c), true
+	case c == '$' && strings.HasPrefix(query[i:], "$$"):
+		if end := strings.Index(query[i+2:], "$$"); end >= 0 {
+			return i + 2 + end + 2, true
+		}
+		return len(query), true
+	case c == '-' && strings.HasPrefix(query[i:], "--"), c == '/' && strings.HasPrefix(query[i:], "//"):
+		if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+			return i + end + 1, true
+		}
+		return len(query), true
+	case c == '/' && strings.HasPrefix(query[i:], "/*"):
+		if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+			return i + 2 + end + 2, true
+		}
+		return len(query), true
+	}
+	return i, false
+}
+
+// skipQuoted returns index after the quoted string started at i, doubled quote is escaped quote
+func skipQuoted(query string, i int, quote byte) int {
+	for i++; i < len(query); i++ {
+		if query[i] == quote {
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+// numInput returns count of the query parameters.
+// For "?NNN" parameters it is the max index, for ":name" parameters it is count of the unique names.
+// Returns -1 if parameters of different kinds are mixed or there are "@name" parameters
+// (they are either parameters or H2 session variables depending on arguments),
+// so count of the arguments is checked when query is executed.
+func numInput(query string) int {
+	var count, max int
+	var numbered, variables bool
+	names := map[string]bool{}
+	scanQuery(query, nil, func(p placeholder) {
+		switch {
+		case p.variable:
+			variables = true
+		case len(p.name) > 0:
+			names[p.name] = true
+		case p.index == 0:
+			count++
+		default:
+			numbered = true
+			if p.index > max {
+				max = p.index
+			}
+		}
+	})
+	switch {
+	case variables, len(names) > 0 && (numbered || count > 0), numbered && count > 0:
+		return -1
+	case len(names) > 0:
+		return len(names)
+	case numbered:
+		return max
+	default:
+		return count
+	}
+}
+
+// bindNamed replaces ":name" placeholders and "@name" placeholders with the named argument of the same name by "?"
+// and returns arguments in the order of the placeholders (the same name may be used several times).
+// "@name" without the argument is H2 session variable, it is left as is.
+// Query without named placeholders is not changed.
+// Error is returned if argument for the placeholder is missing, named argument is not used,
+// positional argument is used with named placeholders or named and positional placeholders are mixed.
+func bindNamed(query string, args []driver.NamedValue) (string, []driver.NamedValue, error) {
+	values := make(map[string]driver.NamedValue, len(args))
+	for _, a := range args {
+		if len(a.Name) > 0 {
+			values[a.Name] = a
+		}
+	}
+	// bind returns true if placeholder is replaced by argument
+	bind := func(p placeholder) bool {
+		if !p.variable {
+			return len(p.name) > 0
+		}
+		_, ok := values[p.name]
+		return ok
+	}
+
+	var named, positional int
+	scanQuery(query, nil, func(p placeholder) {
+		switch {
+		case bind(p):
+			named++
+		case len(p.name) == 0:
+			positional++
+		}
+	})
+
+	if named == 0 {
+		for _, a := range args {
+			if len(a.Name) > 0 {
+				return "", nil, errors.Errorf("named argument \"%s\" requires \":%s\" or \"@%s\" placeholder in query",
+					a.Name, a.Name, a.Name)
+			}
+		}
+		return query, args, nil
+	}
+	if positional > 0 {
+		return "", nil, errors.Errorf("named and positional parameters can't be mixed in query")
+	}
+	for _, a := range args {
+		if len(a.Name) == 0 {
+			return "", nil, errors.Errorf("positional argument %d is used with named parameters", a.Ordinal)
+		}
+	}
+
+	var b strings.Builder
+	bound := make([]driver.NamedValue, 0, named)
+	used := make(map[string]bool, len(values))
+	var err error
+	scanQuery(query, func(s string) {
+		b.WriteString(s)
+	}, func(p placeholder) {
+		if !bind(p) {
+			// H2 session variable
+			b.WriteString(query[p.start:p.end])
+			return
+		}
+		a, ok := values[p.name]
+		if !ok {
+			if err == nil {
+				err = errors.Errorf("missing argument for parameter \"%s\"", query[p.start:p.end])
+			}
+			return
+		}
+		used[p.name] = true
+		b.WriteByte('?')
+		bound = append(bound, driver.NamedValue{Ordinal: len(bound) + 1, Value: a.Value})
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	for _, a := range args {
+		if !used[a.Name] {
+			return "", nil, errors.Errorf("argument \"%s\" is not used in query", a.Name)
+		}
+	}
+	return b.String(), bound, nil
+}
+
+// isNameStart returns true if c can start parameter name
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+// statementType returns ignite.StatementTypeSelect for queries returning rows (SELECT, WITH, EXPLAIN, etc.),
+// ignite.StatementTypeUpdate for DML and DDL statements and ignite.StatementTypeAny if the type is unknown.
+func statementType(query string) byte {
+	q := stripComments(query)
+	if end := strings.IndexFunc(q, func(r rune) bool { return !unicode.IsLetter(r) }); end >= 0 {
+		q = q[:end]
+	}
+	keyword := strings.ToUpper(q)
+
+	switch keyword {
+	case "SELECT", "WITH", "EXPLAIN", "SHOW", "VALUES", "TABLE":
+		return ignite.StatementTypeSelect
+	case "INSERT", "UPDATE", "DELETE", "MERGE", "CREATE", "DROP", "ALTER", "SET", "COPY", "ANALYZE":
+		return ignite.StatementTypeUpdate
+	default:
+		return ignite.StatementTypeAny
+	}
+}
+
+// stripComments removes leading whitespaces, parentheses and comments of the query
+func stripComments(query string) string {
+	for {
+		q := strings.TrimLeftFunc(query, func(r rune) bool { return unicode.IsSpace(r) || r == '(' })
+		switch {
+		case strings.HasPrefix(q, "--"), strings.HasPrefix(q, "//"):
+			if end := strings.IndexByte(q, '\n'); end >= 0 {
+				query = q[end+1:]
+				continue
+			}
+			return ""
+		case strings.HasPrefix(q, "/*"):
+			if end := strings.Index(q[2:], "*/"); end >= 0 {
+				query = q[2+end+2:]
+				continue
+			}
+			return ""
+		}
+		return q
+	}
+}
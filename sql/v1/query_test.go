@@ -0,0 +1,199 @@
+package v1
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/amsokol/ignite-go-client/binary/v1"
+)
+
+func Test_numInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{
+			name:  "success test 1",
+			query: "SELECT * FROM Organization WHERE id = ? AND name = ?",
+			want:  2,
+		},
+		{
+			name:  "success test 2",
+			query: "SELECT '?', \"a?\", 'it''s ?' FROM T WHERE id = ? -- ?\n/* ? */ // ?",
+			want:  1,
+		},
+		{
+			name:  "success test 3",
+			query: "SELECT * FROM T WHERE a = ?2 OR b = ?1 OR c = ?2",
+			want:  2,
+		},
+		{
+			name:  "success test 4",
+			query: "SELECT * FROM T WHERE a = ?1 OR b = ?",
+			want:  -1,
+		},
+		{
+			name:  "success test 5",
+			query: "CREATE ALIAS F AS $$ String f(String s) { return s + \"?\"; } $$",
+			want:  0,
+		},
+		{
+			name:  "success test 6",
+			query: "SELECT 'unterminated ?",
+			want:  0,
+		},
+		{
+			name:  "success test 7",
+			query: "SELECT * FROM T WHERE a = :id OR b = :name OR c = :id AND d = CAST(e AS INT)::INT AND f = ':x'",
+			want:  2,
+		},
+		{
+			name:  "success test 9",
+			// "@x" is either parameter or session variable depending on arguments
+			query: "SET @x = 1; SELECT @x, @@y FROM T WHERE a = ?",
+			want:  -1,
+		},
+		{
+			name:  "success test 8",
+			query: "SELECT * FROM T WHERE a = :id OR b = ?",
+			want:  -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := numInput(tt.query); got != tt.want {
+				t.Errorf("numInput() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_statementType(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  byte
+	}{
+		{
+			name:  "success test 1",
+			query: "  select * from T",
+			want:  ignite.StatementTypeSelect,
+		},
+		{
+			name:  "success test 2",
+			query: "/* comment */ -- line\n(SELECT 1) UNION (SELECT 2)",
+			want:  ignite.StatementTypeSelect,
+		},
+		{
+			name:  "success test 3",
+			query: "INSERT INTO T(ID) VALUES(?)",
+			want:  ignite.StatementTypeUpdate,
+		},
+		{
+			name:  "success test 4",
+			query: "create table T (ID int primary key)",
+			want:  ignite.StatementTypeUpdate,
+		},
+		{
+			name:  "success test 5",
+			query: "CALL F()",
+			want:  ignite.StatementTypeAny,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statementType(tt.query); got != tt.want {
+				t.Errorf("statementType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_bindNamed(t *testing.T) {
+	named := func(name string, v interface{}) driver.NamedValue {
+		return driver.NamedValue{Name: name, Value: v}
+	}
+	positional := func(ordinal int, v interface{}) driver.NamedValue {
+		return driver.NamedValue{Ordinal: ordinal, Value: v}
+	}
+	tests := []struct {
+		name      string
+		query     string
+		args      []driver.NamedValue
+		wantQuery string
+		wantArgs  []driver.NamedValue
+		wantErr   bool
+	}{
+		{
+			name:      "named",
+			query:     "SELECT * FROM T WHERE a = :id AND b = @name AND c = :id AND d = ':id' AND e = f::INT",
+			args:      []driver.NamedValue{named("name", "x"), named("id", int64(5))},
+			wantQuery: "SELECT * FROM T WHERE a = ? AND b = ? AND c = ? AND d = ':id' AND e = f::INT",
+			wantArgs:  []driver.NamedValue{positional(1, int64(5)), positional(2, "x"), positional(3, int64(5))},
+		},
+		{
+			name:      "session variable",
+			query:     "SELECT @x, @@y FROM T WHERE a = ?",
+			args:      []driver.NamedValue{positional(1, int64(5))},
+			wantQuery: "SELECT @x, @@y FROM T WHERE a = ?",
+			wantArgs:  []driver.NamedValue{positional(1, int64(5))},
+		},
+		{
+			name:      "session variable with named parameters",
+			query:     "SELECT @x FROM T WHERE a = :id OR b = @id",
+			args:      []driver.NamedValue{named("id", int64(5))},
+			wantQuery: "SELECT @x FROM T WHERE a = ? OR b = ?",
+			wantArgs:  []driver.NamedValue{positional(1, int64(5)), positional(2, int64(5))},
+		},
+		{
+			name:      "positional",
+			query:     "SELECT * FROM T WHERE a = ? AND b = ?",
+			args:      []driver.NamedValue{positional(1, int64(5)), positional(2, "x")},
+			wantQuery: "SELECT * FROM T WHERE a = ? AND b = ?",
+			wantArgs:  []driver.NamedValue{positional(1, int64(5)), positional(2, "x")},
+		},
+		{
+			name:    "missing argument",
+			query:   "SELECT * FROM T WHERE a = :id AND b = :name",
+			args:    []driver.NamedValue{named("id", int64(5))},
+			wantErr: true,
+		},
+		{
+			name:    "extra argument",
+			query:   "SELECT * FROM T WHERE a = :id",
+			args:    []driver.NamedValue{named("id", int64(5)), named("name", "x")},
+			wantErr: true,
+		},
+		{
+			name:    "named argument with positional placeholders",
+			query:   "SELECT * FROM T WHERE a = ?",
+			args:    []driver.NamedValue{named("id", int64(5))},
+			wantErr: true,
+		},
+		{
+			name:    "positional argument with named placeholders",
+			query:   "SELECT * FROM T WHERE a = :id",
+			args:    []driver.NamedValue{positional(1, int64(5))},
+			wantErr: true,
+		},
+		{
+			name:    "mixed placeholders",
+			query:   "SELECT * FROM T WHERE a = :id AND b = ?",
+			args:    []driver.NamedValue{named("id", int64(5))},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs, err := bindNamed(tt.query, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("bindNamed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotQuery != tt.wantQuery || !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("bindNamed() = %q, %v, want %q, %v", gotQuery, gotArgs, tt.wantQuery, tt.wantArgs)
+			}
+		})
+	}
+}
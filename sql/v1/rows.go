@@ -3,26 +3,47 @@ package v1
 import (
 	"context"
 	"database/sql/driver"
-	"fmt"
 	"io"
 	"runtime"
 
 	"github.com/amsokol/ignite-go-client/binary/errors"
 	"github.com/amsokol/ignite-go-client/binary/v1"
 	"github.com/amsokol/ignite-go-client/debug"
+	"github.com/amsokol/ignite-go-client/sql/common"
 )
 
 // Rows is an iterator over an executed query's results.
 type Rows interface {
 	driver.Rows
+	driver.RowsColumnTypeDatabaseTypeName
+	driver.RowsColumnTypeScanType
+	driver.RowsColumnTypeNullable
+	driver.RowsColumnTypePrecisionScale
 }
 
 type rows struct {
+	// ctx is context of the query, it interrupts fetching of the next pages
+	ctx      context.Context
 	conn     *conn
 	response *ignite.ResponseOperation
 	id       int64
 	fields   []string
 	rowsLeft int
+	// done is true if server cursor is closed
+	done bool
+
+	// first is the first row, it is read in advance to detect column types
+	first []driver.Value
+	// columns is column types detected by values
+	columns []column
+
+	// prefetched is true if rows are read from the decoded pages fetched in background
+	prefetched bool
+	// prefetch fetches the next pages, it is nil if cursor has one page only
+	prefetch *prefetcher
+	// page is the current decoded page and pos is index of the next row in it
+	page page
+	pos  int
 }
 
 // Columns returns the names of the columns. The number of
@@ -35,10 +56,23 @@ func (r *rows) Columns() []string {
 
 // Close closes the rows iterator.
 func (r *rows) Close() error {
-	if r.rowsLeft > 0 {
-		// to prevent resource leak on server try to close cursor
-		r.rowsLeft = 0
-		return r.conn.resourceClose(r.id)
+	if r.prefetch != nil {
+		// wait for the current request, so cursor can be closed
+		r.prefetch.close()
+	}
+	if r.done {
+		return nil
+	}
+	// to prevent resource leak on server try to close cursor
+	r.finish()
+	r.rowsLeft = 0
+	if !r.conn.isConnected() {
+		// cursor is released by server with connection
+		return nil
+	}
+	if err := r.conn.resourceClose(r.id); err != nil && !errors.Is(err, errors.ErrResourceNotFound) {
+		// server closes cursor after the last page itself
+		return err
 	}
 	return nil
 }
@@ -53,8 +87,14 @@ func (r *rows) Close() error {
 // should be taken when closing Rows not to modify
 // a buffer held in dest.
 func (r *rows) Next(dest []driver.Value) error {
+	if r.prefetched {
+		return r.nextPrefetched(dest)
+	}
 	var err error
-	if r.rowsLeft == 0 {
+	for r.rowsLeft == 0 {
+		if r.done {
+			return io.EOF
+		}
 		var hasMore bool
 		if hasMore, err = ignite.ReadBool(r.response); err != nil {
 			// prevent resource leak on server
@@ -62,12 +102,22 @@ func (r *rows) Next(dest []driver.Value) error {
 			return errors.Wrapf(err, "failed to read more records flag")
 		}
 		if !hasMore {
+			// server closes cursor after the last page
+			r.finish()
 			return io.EOF
 		}
-		if r.response, err = r.conn.QueryNexPageContext(context.Background(), r.id); err != nil {
+		if err = r.ctx.Err(); err != nil {
+			// prevent resource leak on server
+			_ = r.Close()
+			return err
+		}
+		if r.response, err = r.conn.QueryNexPageContext(r.ctx, r.id); err != nil {
 			// prevent resource leak on server
 			_ = r.Close()
-			return errors.Wrapf(err, "failed to read cursor page")
+			if err == r.ctx.Err() {
+				return err
+			}
+			return common.NewError(errors.Wrapf(err, "failed to read cursor page"))
 		}
 		// read data
 		var rowCount int32
@@ -81,17 +131,44 @@ func (r *rows) Next(dest []driver.Value) error {
 	if len(r.fields) != len(dest) {
 		return errors.Errorf("destination slice size must be %d but got %d", len(r.fields), len(dest))
 	}
-	for i := 0; i < len(r.fields); i++ {
-		if dest[i], err = ignite.ReadObject(r.response); err != nil {
-			return fmt.Errorf("failed to read field value with index %d: %v", i, err)
-		}
+	if r.first != nil {
+		copy(dest, r.first)
+		r.first = nil
+	} else if err = r.readRow(dest); err != nil {
+		return err
 	}
 	r.rowsLeft--
 	return nil
 }
 
-// newRows creates new Rows object
-func newRows(conn *conn, r *ignite.ResponseOperation) (driver.Rows, error) {
+// readRow reads field values of the row and detects column types
+func (r *rows) readRow(dest []driver.Value) error {
+	return readValues(r.response, dest, r.columns)
+}
+
+// readValues reads field values of the row and updates column types
+func readValues(r *ignite.ResponseOperation, dest []driver.Value, columns []column) error {
+	for i := range dest {
+		t, v, err := ignite.ReadObjectWithType(r)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read field value with index %d", i)
+		}
+		v = rowValue(v)
+		columns[i].observe(t, v)
+		dest[i] = v
+	}
+	return nil
+}
+
+// finish marks server cursor as closed
+func (r *rows) finish() {
+	r.done = true
+	r.conn.closeCursor(r.id)
+}
+
+// newRows creates new Rows object.
+// If prefetchPages is positive, the first page is decoded and up to prefetchPages next pages are fetched in background.
+func newRows(ctx context.Context, conn *conn, r *ignite.ResponseOperation, prefetchPages int) (driver.Rows, error) {
 	var err error
 	// read field names
 	var id int64
@@ -112,6 +189,28 @@ func newRows(conn *conn, r *ignite.ResponseOperation) (driver.Rows, error) {
 		fields = append(fields, s)
 	}
 
+	if prefetchPages > 0 {
+		pg, err := readPage(r, len(fields))
+		if err != nil {
+			return nil, err
+		}
+		rs := &rows{
+			ctx:        ctx,
+			conn:       conn,
+			id:         id,
+			fields:     fields,
+			columns:    pg.columns,
+			prefetched: true,
+			page:       pg,
+		}
+		conn.openCursor(id)
+		if pg.hasMore {
+			rs.prefetch = startPrefetch(ctx, conn, id, len(fields), prefetchPages)
+		}
+		runtime.SetFinalizer(rs, rowsFinalizer)
+		return rs, nil
+	}
+
 	// read row count
 	var rowCount int32
 	if rowCount, err = ignite.ReadInt(r); err != nil {
@@ -119,12 +218,22 @@ func newRows(conn *conn, r *ignite.ResponseOperation) (driver.Rows, error) {
 	}
 
 	rs := &rows{
+		ctx:      ctx,
 		conn:     conn,
 		response: r,
 		id:       id,
 		fields:   fields,
 		rowsLeft: int(rowCount),
+		columns:  make([]column, len(fields)),
+	}
+	if rowCount > 0 {
+		// read the first row to make column types available before Next is called
+		rs.first = make([]driver.Value, len(fields))
+		if err = rs.readRow(rs.first); err != nil {
+			return nil, err
+		}
 	}
+	conn.openCursor(id)
 	runtime.SetFinalizer(rs, rowsFinalizer)
 
 	return rs, nil
@@ -132,7 +241,7 @@ func newRows(conn *conn, r *ignite.ResponseOperation) (driver.Rows, error) {
 
 // connFinalizer is memory leak spy
 func rowsFinalizer(r *rows) {
-	if r.rowsLeft > 0 {
+	if !r.done {
 		debug.ResourceLeakLogger.Printf("rows with cursor ID=\"%d\" is not closed", r.id)
 		r.Close()
 	}
@@ -3,14 +3,18 @@ package v1
 import (
 	"context"
 	"database/sql/driver"
-
-	"github.com/amsokol/ignite-go-client/binary/errors"
 )
 
 // SQL statement struct
 type stmt struct {
-	conn  *conn
-	query string
+	conn     *conn
+	query    string
+	numInput int
+
+	// statement options, initialized by connection options when statement is prepared
+	statementType byte
+	pageSize      int
+	maxRows       int
 
 	driver.Stmt
 	driver.StmtExecContext
@@ -37,7 +41,7 @@ func (s *stmt) Close() error {
 // its number of placeholders. In that case, the sql package
 // will not sanity check Exec or Query argument counts.
 func (s *stmt) NumInput() int {
-	return -1
+	return s.numInput
 }
 
 // Exec executes a query that doesn't return rows, such
@@ -45,8 +49,7 @@ func (s *stmt) NumInput() int {
 //
 // Deprecated: Drivers should implement StmtExecContext instead (or additionally).
 func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
-	// TODO: implement wrapper
-	return nil, errors.Errorf("Deprecated: Drivers should implement StmtExecContext instead (or additionally)")
+	return s.ExecContext(context.Background(), namedValues(args))
 }
 
 // Query executes a query that may return rows, such as a
@@ -54,8 +57,7 @@ func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
 //
 // Deprecated: Drivers should implement StmtQueryContext instead (or additionally).
 func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
-	// TODO: implement wrapper
-	return nil, errors.Errorf("Deprecated: Drivers should implement StmtQueryContext instead (or additionally)")
+	return s.QueryContext(context.Background(), namedValues(args))
 }
 
 // </driver.Stmt>
@@ -67,7 +69,14 @@ func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
 //
 // ExecContext must honor the context timeout and return when it is canceled.
 func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
-	return s.conn.ExecContext(ctx, s.query, args)
+	d, err := s.conn.queryData(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	d.PageSize = 10000
+	d.MaxRows = 0
+	d.StatementType = s.statementType
+	return s.conn.exec(ctx, d)
 }
 
 // </StmtExecContext>
@@ -79,12 +88,34 @@ func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (drive
 //
 // QueryContext must honor the context timeout and return when it is canceled.
 func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-	return s.conn.QueryContext(ctx, s.query, args)
+	d, err := s.conn.queryData(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	d.PageSize = s.pageSize
+	d.MaxRows = s.maxRows
+	d.StatementType = s.statementType
+	return s.conn.query(ctx, d)
 }
 
 // </StmtQueryContext>
 
-// NewStmt creates new Stmt object
+// namedValues converts values to ordinal named values
+func namedValues(args []driver.Value) []driver.NamedValue {
+	if args == nil {
+		return nil
+	}
+	values := make([]driver.NamedValue, 0, len(args))
+	for i, v := range args {
+		values = append(values, driver.NamedValue{Ordinal: i + 1, Value: v})
+	}
+	return values
+}
+
+// NewStmt creates new Stmt object.
+// Statement type is detected by the first keyword of the query,
+// page size and max rows are taken from the connection options.
 func newStmt(conn *conn, query string) driver.Stmt {
-	return &stmt{conn: conn, query: query}
+	return &stmt{conn: conn, query: query, numInput: numInput(query), statementType: statementType(query),
+		pageSize: conn.info.PageSize, maxRows: conn.info.MaxRows}
 }
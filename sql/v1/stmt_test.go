@@ -0,0 +1,89 @@
+package v1
+
+import (
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/amsokol/ignite-go-client/binary/v1"
+	"github.com/amsokol/ignite-go-client/ignitetest"
+	"github.com/amsokol/ignite-go-client/sql/common"
+)
+
+// newTestConn connects to the fake server
+func newTestConn(t *testing.T, h ignitetest.SQLHandler, ci common.ConnInfo) *conn {
+	srv, err := ignitetest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	srv.SQL = h
+
+	ci.ConnInfo = srv.ConnInfo()
+	c, err := Connect(ci)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c.(*conn)
+}
+
+func Test_stmt(t *testing.T) {
+	var queries []ignitetest.SQLQuery
+	c := newTestConn(t, func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		queries = append(queries, q)
+		if q.StatementType == ignite.StatementTypeUpdate {
+			return &ignitetest.SQLResult{Columns: []string{"UPDATED"}, Rows: [][]interface{}{{int64(1)}}}, nil
+		}
+		return &ignitetest.SQLResult{Columns: []string{"NAME"}, Rows: [][]interface{}{{"a"}, {"b"}, {"c"}}}, nil
+	}, common.ConnInfo{PageSize: 2, MaxRows: 100})
+
+	s, err := c.Prepare("INSERT INTO T(ID, NAME) VALUES(?, '?')")
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if n := s.NumInput(); n != 1 {
+		t.Errorf("NumInput() = %d, want 1", n)
+	}
+	res, err := s.Exec([]driver.Value{int64(1)})
+	if err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		t.Errorf("RowsAffected() = %d, want 1", n)
+	}
+
+	s, err = c.Prepare("SELECT NAME FROM T WHERE ID > ?")
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	r, err := s.Query([]driver.Value{int64(0)})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	var names []interface{}
+	dest := make([]driver.Value, 1)
+	for {
+		if err = r.Next(dest); err != nil {
+			break
+		}
+		names = append(names, dest[0])
+	}
+	if err != io.EOF {
+		t.Errorf("Next() error = %v, want io.EOF", err)
+	}
+	if !reflect.DeepEqual(names, []interface{}{"a", "b", "c"}) {
+		t.Errorf("Query() rows = %v, want [a b c]", names)
+	}
+
+	want := []ignitetest.SQLQuery{
+		{Schema: "", Query: "INSERT INTO T(ID, NAME) VALUES(?, '?')", Args: []interface{}{int64(1)},
+			StatementType: ignite.StatementTypeUpdate, PageSize: 10000},
+		{Schema: "", Query: "SELECT NAME FROM T WHERE ID > ?", Args: []interface{}{int64(0)},
+			StatementType: ignite.StatementTypeSelect, PageSize: 2, MaxRows: 100},
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("queries = %+v, want %+v", queries, want)
+	}
+}
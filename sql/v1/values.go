@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"database/sql/driver"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amsokol/ignite-go-client/binary/errors"
+	"github.com/amsokol/ignite-go-client/binary/v1"
+)
+
+// ValueConverter converts query arguments to the types supported by Apache Ignite binary protocol:
+//   - types supported by ignite.WriteObject (int16, int32, ignite.Char, ignite.Date, uuid.UUID, arrays, etc.) are not changed;
+//   - driver.Valuer is replaced by its value;
+//   - int8, uint16, uint32, uint64 and uint are converted to the wider signed integer types
+//     (error is returned if value overflows int64);
+//   - types based on the basic types (e.g. type Status string) are converted to the basic types;
+//   - pointers are dereferenced.
+//
+// Error is returned for the other types.
+var ValueConverter driver.ValueConverter = valueConverter{}
+
+type valueConverter struct{}
+
+// ConvertValue converts a value to a driver Value
+func (valueConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	switch v.(type) {
+	case nil,
+		byte, int16, int32, int64, int, float32, float64, bool, string,
+		ignite.Char, ignite.Date, ignite.Time, time.Time, uuid.UUID,
+		[]byte, []int16, []int32, []int64, []int, []float32, []float64, []bool, []string,
+		[]ignite.Char, []ignite.Date, []ignite.Time, []time.Time, []uuid.UUID,
+		ignite.ComplexObject, *ignite.ComplexObject:
+		return v, nil
+	case driver.Valuer:
+		return convertValuer(v.(driver.Valuer))
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return valueConverter{}.ConvertValue(rv.Elem().Interface())
+	case reflect.Int8:
+		return byte(rv.Int()), nil
+	case reflect.Int16:
+		return int16(rv.Int()), nil
+	case reflect.Int32:
+		return int32(rv.Int()), nil
+	case reflect.Int64, reflect.Int:
+		return rv.Int(), nil
+	case reflect.Uint8:
+		return byte(rv.Uint()), nil
+	case reflect.Uint16:
+		return int32(rv.Uint()), nil
+	case reflect.Uint32:
+		return int64(rv.Uint()), nil
+	case reflect.Uint64, reflect.Uint:
+		u := rv.Uint()
+		if u > math.MaxInt64 {
+			return nil, errors.Errorf("unsigned integer value %d of type %T overflows int64", u, v)
+		}
+		return int64(u), nil
+	case reflect.Float32:
+		return float32(rv.Float()), nil
+	case reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Bytes(), nil
+		}
+	}
+	return nil, errors.Errorf("unsupported argument type %T", v)
+}
+
+// convertValuer returns converted value of the driver.Valuer
+func convertValuer(vr driver.Valuer) (driver.Value, error) {
+	// nil pointer with value receiver method panics, so nil pointer is NULL
+	if rv := reflect.ValueOf(vr); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, nil
+	}
+	v, err := vr.Value()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get value of %T", vr)
+	}
+	if _, ok := v.(driver.Valuer); ok {
+		return nil, errors.Errorf("value of %T is driver.Valuer %T", vr, v)
+	}
+	return valueConverter{}.ConvertValue(v)
+}
+
+// rowValue converts value read from server to the type database/sql can scan into the standard types:
+// ignite.Char and uuid.UUID are converted to string.
+func rowValue(v interface{}) driver.Value {
+	switch t := v.(type) {
+	case ignite.Char:
+		return string(rune(t))
+	case uuid.UUID:
+		return t.String()
+	}
+	return v
+}
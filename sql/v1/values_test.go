@@ -0,0 +1,169 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/amsokol/ignite-go-client/binary/v1"
+	"github.com/amsokol/ignite-go-client/ignitetest"
+	"github.com/amsokol/ignite-go-client/sql/common"
+)
+
+type testStatus string
+
+type testValuer struct {
+	v   driver.Value
+	err error
+}
+
+func (v testValuer) Value() (driver.Value, error) {
+	return v.v, v.err
+}
+
+func Test_valueConverter_ConvertValue(t *testing.T) {
+	id := uuid.New()
+	s := "text"
+	var nilString *string
+	var nilTime *ignite.Date
+	tests := []struct {
+		name    string
+		v       interface{}
+		want    driver.Value
+		wantErr bool
+	}{
+		{name: "nil", v: nil, want: nil},
+		{name: "int16", v: int16(1), want: int16(1)},
+		{name: "char", v: ignite.Char('a'), want: ignite.Char('a')},
+		{name: "date", v: ignite.Date(1000), want: ignite.Date(1000)},
+		{name: "uuid", v: id, want: id},
+		{name: "int array", v: []int32{1, 2}, want: []int32{1, 2}},
+		{name: "int8", v: int8(-1), want: byte(0xff)},
+		{name: "uint16", v: uint16(math.MaxUint16), want: int32(math.MaxUint16)},
+		{name: "uint32", v: uint32(math.MaxUint32), want: int64(math.MaxUint32)},
+		{name: "uint64", v: uint64(1), want: int64(1)},
+		{name: "uint64 overflow", v: uint64(math.MaxUint64), wantErr: true},
+		{name: "named string", v: testStatus("ok"), want: "ok"},
+		{name: "pointer", v: &s, want: "text"},
+		{name: "nil pointer", v: nilString, want: nil},
+		{name: "nil valuer pointer", v: nilTime, want: nil},
+		{name: "null string", v: sql.NullString{}, want: nil},
+		{name: "valid string", v: sql.NullString{String: "a", Valid: true}, want: "a"},
+		{name: "valuer", v: testValuer{v: int64(2)}, want: int64(2)},
+		{name: "valuer error", v: testValuer{err: sql.ErrNoRows}, wantErr: true},
+		{name: "valuer of unsupported type", v: testValuer{v: struct{}{}}, wantErr: true},
+		{name: "struct", v: struct{}{}, wantErr: true},
+		{name: "map", v: map[string]int{}, wantErr: true},
+		{name: "complex", v: complex(1, 2), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValueConverter.ConvertValue(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvertValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConvertValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// testConnector returns the same connection
+type testConnector struct {
+	conn driver.Conn
+}
+
+func (c testConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.conn, nil
+}
+
+func (c testConnector) Driver() driver.Driver {
+	return nil
+}
+
+func Test_conn_values(t *testing.T) {
+	id := uuid.New()
+	ts := time.Date(2018, 4, 3, 14, 25, 32, 0, time.UTC)
+	var args []interface{}
+	c := newTestConn(t, func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		args = q.Args
+		return &ignitetest.SQLResult{Columns: []string{"CODE", "ID", "CREATED", "UPDATED"},
+			Rows: [][]interface{}{{ignite.Char('a'), id, ignite.ToDate(ts), nil}}}, nil
+	}, common.ConnInfo{})
+	db := sql.OpenDB(testConnector{conn: c})
+	defer db.Close()
+
+	var code, uid string
+	var char ignite.Char
+	var created time.Time
+	var updated sql.NullTime
+	var gotID uuid.UUID
+	err := db.QueryRow("SELECT CODE, ID, CREATED, UPDATED FROM T WHERE ID = ? AND STATUS = ? AND NUM = ?",
+		id, testStatus("ok"), uint16(7)).Scan(&code, &uid, &created, &updated)
+	if err != nil {
+		t.Fatalf("QueryRow() error = %v", err)
+	}
+	if code != "a" || uid != id.String() || !created.Equal(ts) || updated.Valid {
+		t.Errorf("QueryRow() = %q, %q, %v, %v", code, uid, created, updated)
+	}
+	if want := []interface{}{id, "ok", int32(7)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("query arguments = %#v, want %#v", args, want)
+	}
+	if err = db.QueryRow("SELECT CODE, ID, CREATED, UPDATED FROM T").Scan(&char, &gotID, &created, &updated); err != nil {
+		t.Fatalf("QueryRow() error = %v", err)
+	}
+	if char != 'a' || gotID != id {
+		t.Errorf("QueryRow() = %q, %v", char, gotID)
+	}
+
+	if _, err = db.Exec("DELETE FROM T WHERE ID = ?", struct{}{}); err == nil {
+		t.Errorf("Exec() with unsupported argument type error = nil")
+	}
+}
+
+func Test_conn_namedArgs(t *testing.T) {
+	var queries []ignitetest.SQLQuery
+	c := newTestConn(t, func(q ignitetest.SQLQuery) (*ignitetest.SQLResult, error) {
+		queries = append(queries, q)
+		return &ignitetest.SQLResult{Columns: []string{"UPDATED"}, Rows: [][]interface{}{{int64(1)}}}, nil
+	}, common.ConnInfo{})
+	db := sql.OpenDB(testConnector{conn: c})
+	defer db.Close()
+
+	query := "UPDATE T SET NAME = :name WHERE ID = :id OR PARENT = :id"
+	if _, err := db.Exec(query, sql.Named("id", int64(5)), sql.Named("name", "x")); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	s, err := db.Prepare(query)
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	defer s.Close()
+	if _, err = s.Exec(sql.Named("name", "y"), sql.Named("id", int64(6))); err != nil {
+		t.Fatalf("Stmt.Exec() error = %v", err)
+	}
+	want := []ignitetest.SQLQuery{
+		{Query: "UPDATE T SET NAME = ? WHERE ID = ? OR PARENT = ?", Args: []interface{}{"x", int64(5), int64(5)}},
+		{Query: "UPDATE T SET NAME = ? WHERE ID = ? OR PARENT = ?", Args: []interface{}{"y", int64(6), int64(6)}},
+	}
+	for i := range queries {
+		queries[i] = ignitetest.SQLQuery{Query: queries[i].Query, Args: queries[i].Args}
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("queries = %+v, want %+v", queries, want)
+	}
+
+	if _, err = db.Exec(query, sql.Named("id", int64(5))); err == nil {
+		t.Errorf("Exec() with missing argument error = nil")
+	}
+	if _, err = db.Exec("DELETE FROM T WHERE ID = ?", sql.Named("id", int64(5))); err == nil {
+		t.Errorf("Exec() with named argument for positional parameter error = nil")
+	}
+}